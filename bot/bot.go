@@ -0,0 +1,342 @@
+package bot
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/huangjunwen/golibs/logr"
+
+	"github.com/huangjunwen/feishu-driver/conf"
+	"github.com/huangjunwen/feishu-driver/message"
+	"github.com/huangjunwen/feishu-driver/webhook"
+	"github.com/huangjunwen/feishu-driver/webhook/events"
+)
+
+// Bot 是机器人命令框架: 它将接收到的消息解析为命令 (默认以 "/" 开头)，经过中间件后交给命令的 HandlerFunc 处理,
+// 命令出错/未知命令/参数错误时自动回复; 内置的 help 命令会根据注册的命令生成帮助.
+//
+// 群聊中默认只响应 @ 机器人的消息 (2.0 版本的消息需要使用 BBotOpenId 设置机器人的 open_id)
+type Bot struct {
+	provider         conf.TenantAccessTokenProvider
+	tenantProvider   func(tenantKey string) conf.TenantAccessTokenProvider
+	prefix           string
+	groupMentionOnly bool
+	replyInThread    bool
+	botOpenId        string
+	fallback         HandlerFunc
+	logger           logr.Logger
+	middlewares      []Middleware
+	commands         []*Command
+	commandsByName   map[string]*Command
+	helpCommandName  string
+	disableHelp      bool
+	missingBotOpenId sync.Once
+}
+
+// New 创建 Bot, provider 用于回复消息 (应用商店应用可使用 BTenantProvider 按租户提供);
+// 选项错误时会 panic
+func New(provider conf.TenantAccessTokenProvider, opts ...BotOption) *Bot {
+	b := &Bot{
+		provider:         provider,
+		prefix:           "/",
+		groupMentionOnly: true,
+		replyInThread:    true,
+		logger:           logr.Nop,
+		commandsByName:   map[string]*Command{},
+		helpCommandName:  "help",
+	}
+	b.tenantProvider = func(string) conf.TenantAccessTokenProvider { return b.provider }
+	for _, opt := range opts {
+		if err := opt(b); err != nil {
+			panic(err)
+		}
+	}
+
+	if !b.disableHelp {
+		b.Regist(&Command{
+			Name:    b.helpCommandName,
+			Usage:   "[命令]",
+			Help:    "显示帮助",
+			MaxArgs: 1,
+			Handler: b.help,
+		})
+	}
+	return b
+}
+
+// Regist 注册命令, 名称/别名重复或缺少 Name/Handler 时会 panic
+func (b *Bot) Regist(cmd *Command) {
+	if cmd.Name == "" || cmd.Handler == nil {
+		panic(fmt.Errorf("Command must have Name and Handler"))
+	}
+	for _, name := range append([]string{cmd.Name}, cmd.Aliases...) {
+		if _, ok := b.commandsByName[name]; ok {
+			panic(fmt.Errorf("Duplicated command name %q", name))
+		}
+		b.commandsByName[name] = cmd
+	}
+	b.commands = append(b.commands, cmd)
+}
+
+// Use 添加全局中间件, 对所有命令 (包括 help) 生效
+func (b *Bot) Use(middlewares ...Middleware) {
+	b.middlewares = append(b.middlewares, middlewares...)
+}
+
+// HandleEvent 处理接收消息事件 (*events.Message 或 *events.MessageReceiveV1), 其它事件直接忽略并返回 handled=false.
+//
+// 命令执行出错时会回复错误而不是返回错误 (避免 webhook 重试导致命令重复执行), 返回的错误仅来自回复消息失败
+func (b *Bot) HandleEvent(ctx context.Context, ev interface{}) (handled bool, err error) {
+	req := b.newRequest(ev)
+	if req == nil {
+		return false, nil
+	}
+	if req.ChatType == ChatTypeGroup && b.groupMentionOnly && !req.IsMentioned {
+		return false, nil
+	}
+	return true, b.serve(ctx, req)
+}
+
+// Wrap 返回一个 webhook.PayloadHandler: 消息事件由 Bot 处理 (出错时仅记录日志)，然后交给 next 处理 (next 为 nil 时直接返回 ok)
+func (b *Bot) Wrap(next webhook.PayloadHandler) webhook.PayloadHandler {
+	return func(w http.ResponseWriter, r *http.Request, payload *webhook.Payload) {
+		if _, err := b.HandleEvent(r.Context(), payload.GetEvent()); err != nil {
+			b.logger.Error(err, "Bot handle event error", "uuid", payload.UUID)
+		}
+		if next == nil {
+			w.Write([]byte("ok"))
+			return
+		}
+		next(w, r, payload)
+	}
+}
+
+// WrapFunc 类似于 Wrap, 用于 webhook.PayloadFunc (长连接/重放等场景), next 可以为 nil
+func (b *Bot) WrapFunc(next webhook.PayloadFunc) webhook.PayloadFunc {
+	return func(ctx context.Context, payload *webhook.Payload) error {
+		if _, err := b.HandleEvent(ctx, payload.GetEvent()); err != nil {
+			b.logger.Error(err, "Bot handle event error", "uuid", payload.UUID)
+		}
+		if next == nil {
+			return nil
+		}
+		return next(ctx, payload)
+	}
+}
+
+// newRequest 将事件转换为 Request, 不是用户发送的消息时返回 nil
+func (b *Bot) newRequest(ev interface{}) *Request {
+	req := &Request{
+		Event: ev,
+		bot:   b,
+	}
+
+	switch e := ev.(type) {
+	case *events.Message:
+		req.TenantKey = e.TenantKey
+		req.ChatId = e.OpenChatId
+		req.ChatType = ChatTypeGroup
+		if e.ChatType == "private" {
+			req.ChatType = ChatTypeP2P
+		}
+		req.MessageId = e.OpenMessageId
+		req.RootId = e.RootId
+		req.SenderOpenId = e.OpenId
+		req.IsMentioned = e.IsMention
+		if e.MsgType == "text" {
+			req.Text = strings.TrimSpace(e.TextWithoutAtBot)
+		}
+
+	case *events.MessageReceiveV1:
+		if e.Sender.SenderType != "user" {
+			return nil
+		}
+		req.TenantKey = e.Sender.TenantKey
+		req.ChatId = e.Message.ChatId
+		req.ChatType = ChatTypeGroup
+		if e.Message.ChatType == "p2p" {
+			req.ChatType = ChatTypeP2P
+		}
+		req.MessageId = e.Message.MessageId
+		req.RootId = e.Message.RootId
+		req.SenderOpenId = e.Sender.SenderId.OpenId
+		req.SenderUserId = e.Sender.SenderId.UserId
+		if b.botOpenId != "" {
+			req.IsMentioned = e.IsMentioned(b.botOpenId)
+		} else if req.ChatType == ChatTypeGroup && b.groupMentionOnly {
+			// 无法判断是否 @ 机器人, 视为没有 @
+			b.missingBotOpenId.Do(func() {
+				b.logger.Error(fmt.Errorf("Missing bot open id"), "Group messages (schema 2.0) are ignored, use BBotOpenId to set the bot's open_id")
+			})
+		}
+		if content, err := e.ParseContent(); err == nil {
+			if text, ok := content.(*events.TextContent); ok {
+				req.Text = text.TextWithoutMentions(e.Message.Mentions)
+			}
+		}
+
+	default:
+		return nil
+	}
+
+	return req
+}
+
+func (b *Bot) serve(ctx context.Context, req *Request) error {
+
+	// 非命令
+	if req.Text == "" || !strings.HasPrefix(req.Text, b.prefix) {
+		if b.fallback == nil {
+			return nil
+		}
+		return b.run(ctx, req, b.fallback, nil)
+	}
+
+	args, err := ParseArgs(strings.TrimPrefix(req.Text, b.prefix))
+	if err != nil {
+		return req.ReplyText(ctx, fmt.Sprintf("命令解析错误: %s", err))
+	}
+	if len(args) == 0 {
+		return nil
+	}
+
+	cmd := b.commandsByName[args[0]]
+	if cmd == nil || !cmd.availableIn(req.ChatType) {
+		return req.ReplyText(ctx, fmt.Sprintf("未知命令 %s%s%s", b.prefix, args[0], b.helpHint()))
+	}
+	req.Command = cmd
+	req.Args = args[1:]
+
+	if err := cmd.checkArgs(req.Args); err != nil {
+		return req.ReplyText(ctx, fmt.Sprintf("%s, 用法: %s", err, b.usage(cmd)))
+	}
+	return b.run(ctx, req, cmd.Handler, cmd.Middlewares)
+
+}
+
+// run 经过中间件后执行 handler, 出错时回复错误
+func (b *Bot) run(ctx context.Context, req *Request, handler HandlerFunc, middlewares []Middleware) error {
+	for i := len(middlewares) - 1; i >= 0; i-- {
+		handler = middlewares[i](handler)
+	}
+	for i := len(b.middlewares) - 1; i >= 0; i-- {
+		handler = b.middlewares[i](handler)
+	}
+
+	if err := handler(ctx, req); err != nil {
+		b.logger.Error(err, "Bot command error", "messageId", req.MessageId)
+		return req.ReplyText(ctx, err.Error())
+	}
+	return nil
+}
+
+func (b *Bot) reply(ctx context.Context, req *Request, content message.SendContent) error {
+	send := message.Send{
+		ChatId:  req.ChatId,
+		Content: content,
+	}
+	if b.replyInThread {
+		send.RootId = req.RootId
+		if send.RootId == "" {
+			send.RootId = req.MessageId
+		}
+	}
+
+	provider, err := b.reqProvider(req)
+	if err != nil {
+		return err
+	}
+	res, err := send.Do(ctx, provider)
+	if err != nil {
+		return err
+	}
+	return res.ResultError()
+}
+
+func (b *Bot) replyEphemeral(ctx context.Context, req *Request, content message.SendContent) error {
+	send := message.SendEphemeral{
+		ChatId:  req.ChatId,
+		OpenId:  req.SenderOpenId,
+		Content: content,
+	}
+
+	provider, err := b.reqProvider(req)
+	if err != nil {
+		return err
+	}
+	res, err := send.Do(ctx, provider)
+	if err != nil {
+		return err
+	}
+	return res.ResultError()
+}
+
+func (b *Bot) reqProvider(req *Request) (conf.TenantAccessTokenProvider, error) {
+	provider := b.tenantProvider(req.TenantKey)
+	if provider == nil {
+		return nil, fmt.Errorf("No tenant access token provider for tenant %q", req.TenantKey)
+	}
+	return provider, nil
+}
+
+func (b *Bot) helpHint() string {
+	if b.disableHelp {
+		return ""
+	}
+	return fmt.Sprintf(", 发送 %s%s 查看帮助", b.prefix, b.helpCommandName)
+}
+
+func (b *Bot) usage(cmd *Command) string {
+	usage := b.prefix + cmd.Name
+	if cmd.Usage != "" {
+		usage += " " + cmd.Usage
+	}
+	return usage
+}
+
+// help 是内置的 help 命令
+func (b *Bot) help(ctx context.Context, req *Request) error {
+	lines := []string{}
+
+	// 单个命令的帮助
+	if len(req.Args) == 1 {
+		cmd := b.commandsByName[strings.TrimPrefix(req.Args[0], b.prefix)]
+		if cmd == nil || !cmd.availableIn(req.ChatType) {
+			return fmt.Errorf("未知命令 %s", req.Args[0])
+		}
+		lines = append(lines, b.usage(cmd))
+		if cmd.Help != "" {
+			lines = append(lines, cmd.Help)
+		}
+		if len(cmd.Aliases) != 0 {
+			aliases := make([]string, 0, len(cmd.Aliases))
+			for _, alias := range cmd.Aliases {
+				aliases = append(aliases, b.prefix+alias)
+			}
+			lines = append(lines, "别名: "+strings.Join(aliases, ", "))
+		}
+		return req.ReplyText(ctx, strings.Join(lines, "\n"))
+	}
+
+	cmds := make([]*Command, 0, len(b.commands))
+	for _, cmd := range b.commands {
+		if cmd.availableIn(req.ChatType) {
+			cmds = append(cmds, cmd)
+		}
+	}
+	sort.SliceStable(cmds, func(i, j int) bool { return cmds[i].Name < cmds[j].Name })
+
+	lines = append(lines, "可用命令:")
+	for _, cmd := range cmds {
+		line := b.usage(cmd)
+		if cmd.Help != "" {
+			line += " - " + cmd.Help
+		}
+		lines = append(lines, line)
+	}
+	return req.ReplyText(ctx, strings.Join(lines, "\n"))
+}
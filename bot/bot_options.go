@@ -0,0 +1,84 @@
+package bot
+
+import (
+	"fmt"
+
+	"github.com/huangjunwen/golibs/logr"
+
+	"github.com/huangjunwen/feishu-driver/conf"
+)
+
+// BotOption 是创建 Bot 的选项
+type BotOption func(*Bot) error
+
+// BPrefix 设置命令前缀, 默认为 "/"; 设置为空时所有文本消息都作为命令解析
+func BPrefix(prefix string) BotOption {
+	return func(b *Bot) error {
+		b.prefix = prefix
+		return nil
+	}
+}
+
+// BGroupMentionOnly 设置群聊中是否只响应 @ 机器人的消息, 默认为 true
+func BGroupMentionOnly(mentionOnly bool) BotOption {
+	return func(b *Bot) error {
+		b.groupMentionOnly = mentionOnly
+		return nil
+	}
+}
+
+// BReplyInThread 设置是否在话题中回复 (使用 RootId), 默认为 true
+func BReplyInThread(inThread bool) BotOption {
+	return func(b *Bot) error {
+		b.replyInThread = inThread
+		return nil
+	}
+}
+
+// BBotOpenId 设置机器人的 open_id, 用于判断 2.0 版本消息中机器人是否被 @;
+// 不设置时 2.0 版本的消息均视为没有 @ 机器人 (因此 BGroupMentionOnly 为 true 时群消息会被忽略)
+func BBotOpenId(openId string) BotOption {
+	return func(b *Bot) error {
+		b.botOpenId = openId
+		return nil
+	}
+}
+
+// BTenantProvider 设置按租户获得 tenant access token provider 的函数 (应用商店应用使用), 设置后忽略 New 的 provider
+func BTenantProvider(fn func(tenantKey string) conf.TenantAccessTokenProvider) BotOption {
+	return func(b *Bot) error {
+		if fn == nil {
+			return fmt.Errorf("BTenantProvider: nil function")
+		}
+		b.tenantProvider = fn
+		return nil
+	}
+}
+
+// BFallback 设置非命令消息的处理函数, 默认忽略非命令消息
+func BFallback(fn HandlerFunc) BotOption {
+	return func(b *Bot) error {
+		b.fallback = fn
+		return nil
+	}
+}
+
+// BHelp 设置内置 help 命令的名称, 为空时不注册 help 命令
+func BHelp(name string) BotOption {
+	return func(b *Bot) error {
+		b.helpCommandName = name
+		b.disableHelp = name == ""
+		return nil
+	}
+}
+
+// BLogger 设置日志
+func BLogger(logger logr.Logger) BotOption {
+	return func(b *Bot) error {
+		if logger == nil {
+			logger = logr.Nop
+		}
+		b.logger = logger
+		return nil
+	}
+}
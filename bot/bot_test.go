@@ -0,0 +1,320 @@
+package bot
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/huangjunwen/feishu-driver/conf"
+	"github.com/huangjunwen/feishu-driver/utils"
+	"github.com/huangjunwen/feishu-driver/webhook/events"
+)
+
+type sent struct {
+	ChatId  string `json:"chat_id"`
+	RootId  string `json:"root_id"`
+	MsgType string `json:"msg_type"`
+	Content struct {
+		Text string `json:"text"`
+	} `json:"content"`
+}
+
+func newTestServer(assert *assert.Assertions) (*httptest.Server, *[]sent) {
+	sents := &[]sent{}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal("/message/v4/send", r.URL.Path)
+		assert.Equal("Bearer t-token", r.Header.Get("Authorization"))
+		s := sent{}
+		assert.NoError(json.NewDecoder(r.Body).Decode(&s))
+		*sents = append(*sents, s)
+		w.Write([]byte(`{"code": 0, "msg": "ok", "data": {"message_id": "om_reply"}}`))
+	}))
+	return srv, sents
+}
+
+func TestParseArgs(t *testing.T) {
+	assert := assert.New(t)
+
+	for _, testCase := range []struct {
+		Line     string
+		Expected []string
+	}{
+		{"", []string{}},
+		{"deploy svc  prod", []string{"deploy", "svc", "prod"}},
+		{`echo "hello world" 'a "b"' c\ d ""`, []string{"echo", "hello world", `a "b"`, "c d", ""}},
+	} {
+		args, err := ParseArgs(testCase.Line)
+		assert.NoError(err)
+		assert.Equal(testCase.Expected, args, testCase.Line)
+	}
+
+	_, err := ParseArgs(`echo "abc`)
+	assert.Error(err)
+	_, err = ParseArgs(`echo abc\`)
+	assert.Error(err)
+}
+
+func TestBot(t *testing.T) {
+	assert := assert.New(t)
+
+	srv, sents := newTestServer(assert)
+	defer srv.Close()
+	ctx := utils.APIOptions{URLBase: srv.URL}.WithCtx(context.Background())
+
+	provider := conf.TenantAccessTokenProviderFunc(func() (string, error) { return "t-token", nil })
+	b := New(provider, BBotOpenId("ou_bot"))
+
+	deployed := []string{}
+	b.Regist(&Command{
+		Name:        "deploy",
+		Aliases:     []string{"d"},
+		Usage:       "<svc> <env>",
+		Help:        "发布服务",
+		MinArgs:     2,
+		MaxArgs:     2,
+		ChatTypes:   []string{ChatTypeGroup},
+		Middlewares: []Middleware{AllowOpenIds("ou_admin")},
+		Handler: func(ctx context.Context, req *Request) error {
+			deployed = append(deployed, strings.Join(req.Args, "@"))
+			return req.ReplyText(ctx, "ok")
+		},
+	})
+	b.Regist(&Command{
+		Name: "dept",
+		Middlewares: []Middleware{AllowDepartments(func(ctx context.Context, req *Request) ([]string, error) {
+			return []string{"od_" + req.SenderOpenId}, nil
+		}, "od_ou_admin")},
+		Handler: func(ctx context.Context, req *Request) error {
+			return req.ReplyText(ctx, "dept ok")
+		},
+	})
+	assert.Panics(func() { b.Regist(&Command{Name: "d", Handler: func(context.Context, *Request) error { return nil }}) })
+
+	// 2.0 版本群消息
+	group := func(openId, text string, mentionBot bool) (bool, error) {
+		ev := &events.MessageReceiveV1{}
+		ev.Sender.SenderType = "user"
+		ev.Sender.SenderId.OpenId = openId
+		ev.Message.MessageId = "om_1"
+		ev.Message.ChatId = "oc_1"
+		ev.Message.ChatType = "group"
+		ev.Message.MessageType = "text"
+		content, _ := json.Marshal(map[string]string{"text": text})
+		ev.Message.Content = string(content)
+		if mentionBot {
+			ev.Message.Mentions = []events.MessageMention{{Key: "@_user_1", Id: events.UserId{OpenId: "ou_bot"}}}
+		}
+		return b.HandleEvent(ctx, ev)
+	}
+	// 1.0 版本单聊消息
+	p2p := func(openId, text string) (bool, error) {
+		return b.HandleEvent(ctx, &events.Message{
+			OpenChatId:       "oc_2",
+			ChatType:         "private",
+			MsgType:          "text",
+			OpenId:           openId,
+			OpenMessageId:    "om_2",
+			RootId:           "om_root",
+			TextWithoutAtBot: text,
+		})
+	}
+	lastReply := func() sent {
+		if len(*sents) == 0 {
+			return sent{}
+		}
+		return (*sents)[len(*sents)-1]
+	}
+
+	// 群聊中没有 @ 机器人: 忽略
+	{
+		handled, err := group("ou_admin", "/deploy svc prod", false)
+		assert.False(handled)
+		assert.NoError(err)
+		assert.Len(*sents, 0)
+	}
+
+	// 命令及别名, 在话题中回复
+	{
+		handled, err := group("ou_admin", "@_user_1 /deploy svc prod", true)
+		assert.True(handled)
+		assert.NoError(err)
+		_, err = group("ou_admin", "@_user_1 /d svc2 \"pre prod\"", true)
+		assert.NoError(err)
+		assert.Equal([]string{"svc@prod", "svc2@pre prod"}, deployed)
+		assert.Equal(sent{ChatId: "oc_1", RootId: "om_1", MsgType: "text", Content: lastReply().Content}, lastReply())
+		assert.Equal("ok", lastReply().Content.Text)
+	}
+
+	// 权限
+	{
+		_, err := group("ou_other", "@_user_1 /deploy svc prod", true)
+		assert.NoError(err)
+		assert.Equal("没有权限", lastReply().Content.Text)
+		_, err = p2p("ou_other", "/dept")
+		assert.NoError(err)
+		assert.Equal("没有权限", lastReply().Content.Text)
+		_, err = p2p("ou_admin", "/dept")
+		assert.NoError(err)
+		assert.Equal("dept ok", lastReply().Content.Text)
+		assert.Equal("om_root", lastReply().RootId)
+	}
+
+	// 参数错误/未知命令/限定会话类型
+	{
+		group("ou_admin", "@_user_1 /deploy svc", true)
+		assert.Equal("参数错误, 用法: /deploy <svc> <env>", lastReply().Content.Text)
+		p2p("ou_admin", "/xxx")
+		assert.Equal("未知命令 /xxx, 发送 /help 查看帮助", lastReply().Content.Text)
+		p2p("ou_admin", "/deploy svc prod")
+		assert.Equal("未知命令 /deploy, 发送 /help 查看帮助", lastReply().Content.Text)
+	}
+
+	// 非命令: 忽略
+	{
+		n := len(*sents)
+		handled, err := p2p("ou_admin", "hello")
+		assert.True(handled)
+		assert.NoError(err)
+		assert.Len(*sents, n)
+	}
+
+	// 帮助
+	{
+		group("ou_admin", "@_user_1 /help", true)
+		assert.Equal("可用命令:\n/deploy <svc> <env> - 发布服务\n/dept\n/help [命令] - 显示帮助", lastReply().Content.Text)
+		p2p("ou_admin", "/help")
+		assert.Equal("可用命令:\n/dept\n/help [命令] - 显示帮助", lastReply().Content.Text)
+		group("ou_admin", "@_user_1 /help d", true)
+		assert.Equal("/deploy <svc> <env>\n发布服务\n别名: /d", lastReply().Content.Text)
+	}
+
+	// 其它事件
+	{
+		handled, err := b.HandleEvent(ctx, &events.UserAdd{})
+		assert.False(handled)
+		assert.NoError(err)
+	}
+}
+
+func TestBotOptions(t *testing.T) {
+	assert := assert.New(t)
+
+	srv, sents := newTestServer(assert)
+	defer srv.Close()
+	ctx := utils.APIOptions{URLBase: srv.URL}.WithCtx(context.Background())
+
+	tenants := map[string]conf.TenantAccessTokenProvider{
+		"t1": conf.TenantAccessTokenProviderFunc(func() (string, error) { return "t-token", nil }),
+	}
+	b := New(nil,
+		BPrefix("!"),
+		BGroupMentionOnly(false),
+		BReplyInThread(false),
+		BHelp(""),
+		BTenantProvider(func(tenantKey string) conf.TenantAccessTokenProvider { return tenants[tenantKey] }),
+		BFallback(func(ctx context.Context, req *Request) error {
+			return req.ReplyText(ctx, fmt.Sprintf("echo: %s", req.Text))
+		}),
+	)
+
+	handled, err := b.HandleEvent(ctx, &events.Message{TenantKey: "t1", OpenChatId: "oc_1", ChatType: "group", MsgType: "text", OpenMessageId: "om_1", TextWithoutAtBot: "hi"})
+	assert.True(handled)
+	assert.NoError(err)
+	assert.Equal([]sent{{ChatId: "oc_1", MsgType: "text", Content: (*sents)[0].Content}}, *sents)
+	assert.Equal("echo: hi", (*sents)[0].Content.Text)
+
+	b.HandleEvent(ctx, &events.Message{TenantKey: "t1", OpenChatId: "oc_1", ChatType: "group", MsgType: "text", TextWithoutAtBot: "!help"})
+	assert.Equal("未知命令 !help", (*sents)[1].Content.Text)
+
+	// 没有租户的 provider
+	_, err = b.HandleEvent(ctx, &events.Message{TenantKey: "t2", ChatType: "group", MsgType: "text", TextWithoutAtBot: "hi"})
+	assert.Error(err)
+}
+
+func TestBotEphemeral(t *testing.T) {
+	assert := assert.New(t)
+
+	paths := []string{}
+	bodies := []map[string]interface{}{}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body := map[string]interface{}{}
+		assert.NoError(json.NewDecoder(r.Body).Decode(&body))
+		paths = append(paths, r.URL.Path)
+		bodies = append(bodies, body)
+		w.Write([]byte(`{"code": 0, "msg": "ok", "data": {"message_id": "om_reply"}}`))
+	}))
+	defer srv.Close()
+	ctx := utils.APIOptions{URLBase: srv.URL}.WithCtx(context.Background())
+
+	provider := conf.TenantAccessTokenProviderFunc(func() (string, error) { return "t-token", nil })
+	b := New(provider, BGroupMentionOnly(false))
+	b.Regist(&Command{
+		Name: "whoami",
+		Handler: func(ctx context.Context, req *Request) error {
+			return req.ReplyEphemeralText(ctx, req.SenderOpenId)
+		},
+	})
+
+	// 群聊: 临时卡片
+	_, err := b.HandleEvent(ctx, &events.Message{OpenChatId: "oc_1", ChatType: "group", MsgType: "text", OpenId: "ou_1", OpenMessageId: "om_1", TextWithoutAtBot: "/whoami"})
+	assert.NoError(err)
+	assert.Equal([]string{"/ephemeral/v1/send"}, paths)
+	assert.Equal("oc_1", bodies[0]["chat_id"])
+	assert.Equal("ou_1", bodies[0]["open_id"])
+	assert.Equal("interactive", bodies[0]["msg_type"])
+	assert.NotNil(bodies[0]["card"])
+
+	// 单聊: 普通回复
+	_, err = b.HandleEvent(ctx, &events.Message{OpenChatId: "oc_2", ChatType: "private", MsgType: "text", OpenId: "ou_1", OpenMessageId: "om_2", TextWithoutAtBot: "/whoami"})
+	assert.NoError(err)
+	assert.Equal([]string{"/ephemeral/v1/send", "/message/v4/send"}, paths)
+	assert.Equal("text", bodies[1]["msg_type"])
+}
+
+func TestBotWithoutBotOpenId(t *testing.T) {
+	assert := assert.New(t)
+
+	srv, sents := newTestServer(assert)
+	defer srv.Close()
+	ctx := utils.APIOptions{URLBase: srv.URL}.WithCtx(context.Background())
+
+	provider := conf.TenantAccessTokenProviderFunc(func() (string, error) { return "t-token", nil })
+	b := New(provider)
+	b.Regist(&Command{
+		Name: "deploy",
+		Handler: func(ctx context.Context, req *Request) error {
+			return req.ReplyText(ctx, "ok")
+		},
+	})
+
+	newEvent := func(chatType string) *events.MessageReceiveV1 {
+		ev := &events.MessageReceiveV1{}
+		ev.Sender.SenderType = "user"
+		ev.Sender.SenderId.OpenId = "ou_1"
+		ev.Message.MessageId = "om_1"
+		ev.Message.ChatId = "oc_1"
+		ev.Message.ChatType = chatType
+		ev.Message.MessageType = "text"
+		ev.Message.Content = `{"text": "@_user_1 /deploy svc prod"}`
+		ev.Message.Mentions = []events.MessageMention{{Key: "@_user_1", Id: events.UserId{OpenId: "ou_alice"}, Name: "Alice"}}
+		return ev
+	}
+
+	// 群聊中 @ 其他人: 无法判断是否 @ 机器人, 忽略
+	handled, err := b.HandleEvent(ctx, newEvent("group"))
+	assert.False(handled)
+	assert.NoError(err)
+	assert.Len(*sents, 0)
+
+	// 单聊不受影响
+	handled, err = b.HandleEvent(ctx, newEvent("p2p"))
+	assert.True(handled)
+	assert.NoError(err)
+	assert.Len(*sents, 1)
+}
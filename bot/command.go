@@ -0,0 +1,112 @@
+package bot
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"unicode"
+)
+
+// HandlerFunc 处理命令, 返回的错误会回复给用户
+type HandlerFunc func(ctx context.Context, req *Request) error
+
+// Middleware 包装 HandlerFunc, 可用于权限控制/日志等
+type Middleware func(next HandlerFunc) HandlerFunc
+
+// Command 是一个命令
+type Command struct {
+	// Name 是命令名称 (不含前缀), 必须填写
+	Name string
+
+	// Aliases 是命令别名
+	Aliases []string
+
+	// Usage 是参数说明, 如 "<svc> <env>"
+	Usage string
+
+	// Help 是命令说明
+	Help string
+
+	// MinArgs/MaxArgs 是参数个数范围, MaxArgs 为 0 表示不限
+	MinArgs int
+	MaxArgs int
+
+	// ChatTypes 限制命令可用的会话类型 (ChatTypeP2P/ChatTypeGroup), 空表示不限
+	ChatTypes []string
+
+	// Middlewares 是该命令的中间件, 在 Bot 的中间件之后执行
+	Middlewares []Middleware
+
+	// Handler 处理命令, 必须填写
+	Handler HandlerFunc
+}
+
+// availableIn 判断命令是否可在该会话类型中使用
+func (cmd *Command) availableIn(chatType string) bool {
+	if len(cmd.ChatTypes) == 0 {
+		return true
+	}
+	for _, typ := range cmd.ChatTypes {
+		if typ == chatType {
+			return true
+		}
+	}
+	return false
+}
+
+// checkArgs 检查参数个数
+func (cmd *Command) checkArgs(args []string) error {
+	if len(args) < cmd.MinArgs || (cmd.MaxArgs > 0 && len(args) > cmd.MaxArgs) {
+		return fmt.Errorf("参数错误")
+	}
+	return nil
+}
+
+// ParseArgs 将命令行按空白分隔为参数, 支持单/双引号及反斜杠转义
+func ParseArgs(line string) ([]string, error) {
+	var (
+		args    = []string{}
+		b       = &strings.Builder{}
+		inArg   = false
+		quote   = rune(0)
+		escaped = false
+	)
+	for _, r := range line {
+		switch {
+		case escaped:
+			b.WriteRune(r)
+			escaped = false
+		case r == '\\' && quote != '\'':
+			escaped = true
+			inArg = true
+		case quote != 0:
+			if r == quote {
+				quote = 0
+			} else {
+				b.WriteRune(r)
+			}
+		case r == '"' || r == '\'':
+			quote = r
+			inArg = true
+		case unicode.IsSpace(r):
+			if inArg {
+				args = append(args, b.String())
+				b.Reset()
+				inArg = false
+			}
+		default:
+			b.WriteRune(r)
+			inArg = true
+		}
+	}
+	if quote != 0 {
+		return nil, fmt.Errorf("Unterminated quote")
+	}
+	if escaped {
+		return nil, fmt.Errorf("Trailing backslash")
+	}
+	if inArg {
+		args = append(args, b.String())
+	}
+	return args, nil
+}
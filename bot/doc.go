@@ -0,0 +1,3 @@
+// Package bot 是基于接收消息事件的机器人命令框架: 命令注册 (名称/别名/参数/帮助)，中间件 (如权限控制)，
+// 自动回复 (在话题中回复) 以及自动生成的 /help, 主要功能由 Bot 提供
+package bot
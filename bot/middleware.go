@@ -0,0 +1,45 @@
+package bot
+
+import (
+	"context"
+	"fmt"
+)
+
+// AllowOpenIds 只允许 openIds 中的用户执行命令
+func AllowOpenIds(openIds ...string) Middleware {
+	allowed := map[string]bool{}
+	for _, openId := range openIds {
+		allowed[openId] = true
+	}
+	return func(next HandlerFunc) HandlerFunc {
+		return func(ctx context.Context, req *Request) error {
+			if !allowed[req.SenderOpenId] {
+				return fmt.Errorf("没有权限")
+			}
+			return next(ctx, req)
+		}
+	}
+}
+
+// AllowDepartments 只允许属于 departmentIds 中任一部门的用户执行命令,
+// lookup 用于获得发送者所属的部门 (如调用通讯录接口并缓存)
+func AllowDepartments(lookup func(ctx context.Context, req *Request) ([]string, error), departmentIds ...string) Middleware {
+	allowed := map[string]bool{}
+	for _, departmentId := range departmentIds {
+		allowed[departmentId] = true
+	}
+	return func(next HandlerFunc) HandlerFunc {
+		return func(ctx context.Context, req *Request) error {
+			departments, err := lookup(ctx, req)
+			if err != nil {
+				return err
+			}
+			for _, departmentId := range departments {
+				if allowed[departmentId] {
+					return next(ctx, req)
+				}
+			}
+			return fmt.Errorf("没有权限")
+		}
+	}
+}
@@ -0,0 +1,87 @@
+package bot
+
+import (
+	"context"
+
+	"github.com/huangjunwen/feishu-driver/message"
+	"github.com/huangjunwen/feishu-driver/message/card"
+)
+
+var (
+	// ChatTypeP2P 是单聊
+	ChatTypeP2P = "p2p"
+
+	// ChatTypeGroup 是群聊
+	ChatTypeGroup = "group"
+)
+
+// Request 是一条发给机器人的消息, 同时支持 events.Message (1.0) 及 events.MessageReceiveV1 (2.0)
+type Request struct {
+	// TenantKey 是企业唯一标识
+	TenantKey string
+
+	// ChatId 是会话 id
+	ChatId string
+
+	// ChatType 是会话类型: ChatTypeP2P/ChatTypeGroup
+	ChatType string
+
+	// MessageId 是消息 id
+	MessageId string
+
+	// RootId 是消息所在话题的根消息 id, 不在话题中时为空
+	RootId string
+
+	// SenderOpenId/SenderUserId 是发送者; 1.0 版本事件没有 SenderUserId
+	SenderOpenId string
+	SenderUserId string
+
+	// IsMentioned 表示机器人是否被 @
+	IsMentioned bool
+
+	// Text 是去掉 @ 之后的文本, 非文本消息为空
+	Text string
+
+	// Command 是匹配到的命令, Args 是命令参数; 没有匹配到命令时 Command 为 nil
+	Command *Command
+	Args    []string
+
+	// Event 是原始事件: *events.Message 或 *events.MessageReceiveV1
+	Event interface{}
+
+	bot *Bot
+}
+
+// IsP2P 判断是否单聊
+func (req *Request) IsP2P() bool {
+	return req.ChatType == ChatTypeP2P
+}
+
+// Reply 回复消息到该会话, 开启 BReplyInThread 时 (默认) 在话题中回复
+func (req *Request) Reply(ctx context.Context, content message.SendContent) error {
+	return req.bot.reply(ctx, req, content)
+}
+
+// ReplyText 回复文本消息
+func (req *Request) ReplyText(ctx context.Context, text string) error {
+	return req.Reply(ctx, &message.SendTextContent{Text: text})
+}
+
+// ReplyEphemeral 在群聊中回复仅发送者可见的临时卡片, content 必须是卡片 (*message.SendCardContent/*message.SendTemplateCardContent);
+// 单聊中本身仅双方可见，直接使用 Reply 回复
+func (req *Request) ReplyEphemeral(ctx context.Context, content message.SendContent) error {
+	if req.IsP2P() {
+		return req.Reply(ctx, content)
+	}
+	return req.bot.replyEphemeral(ctx, req, content)
+}
+
+// ReplyEphemeralText 在群聊中以仅包含文本的临时卡片回复, 单聊中使用 ReplyText 回复
+func (req *Request) ReplyEphemeralText(ctx context.Context, text string) error {
+	if req.IsP2P() {
+		return req.ReplyText(ctx, text)
+	}
+	return req.ReplyEphemeral(ctx, &message.SendCardContent{
+		Card: card.New().Add(card.NewDiv(card.PlainText(text))),
+	})
+}
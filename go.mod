@@ -3,6 +3,7 @@ module github.com/huangjunwen/feishu-driver
 go 1.15
 
 require (
+	github.com/gorilla/websocket v1.4.2
 	github.com/huangjunwen/golibs v0.0.0-20201220010403-809281dd3f6e
 	github.com/stretchr/testify v1.6.1
 	github.com/tidwall/gjson v1.6.0
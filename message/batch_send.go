@@ -3,6 +3,7 @@ package message
 import (
 	"context"
 	"fmt"
+	"net/url"
 
 	"github.com/huangjunwen/feishu-driver/conf"
 	"github.com/huangjunwen/feishu-driver/utils"
@@ -14,11 +15,14 @@ type BatchSend struct {
 	OpenIds       []string `json:"open_ids"`
 	UserIds       []string `json:"user_ids"`
 
-	// Content 是实际内容，目前仅支持文本消息
-	Content SendContent `json:"content"`
+	// Content 是实际内容，支持文本/图片/富文本/群名片/卡片消息
+	Content SendContent `json:"content,omitempty"`
 
 	// MsgType 是内容类型，不需要填写，由 Content.SendContentMsgType 获得
 	MsgType string `json:"msg_type"`
+
+	// Card 是卡片内容，不需要填写，Content 为 *SendCardContent/*SendTemplateCardContent 时由其获得
+	Card interface{} `json:"card,omitempty"`
 }
 
 // BatchSendResult 时批量发送消息的结果
@@ -26,6 +30,7 @@ type BatchSendResult struct {
 	utils.APIResultBase
 
 	Data struct {
+		// MessageId 是批量消息的 id (bm_ 开头), 用于查询进度/已读情况及撤回
 		MessageId            string   `json:"message_id"`
 		InvalidDepartmentIds []string `json:"invalid_department_ids"`
 		InvalidOpenIds       []string `json:"invalid_open_ids"`
@@ -33,16 +38,56 @@ type BatchSendResult struct {
 	} `json:"data"`
 }
 
+// BatchMessageProgressResult 是查询批量消息整体进度接口的结果
+type BatchMessageProgressResult struct {
+	utils.APIResultBase
+
+	Data struct {
+		SendProgress struct {
+			// ValidUserIdsCount 是有效的接收用户数
+			ValidUserIdsCount int `json:"valid_user_ids_count"`
+			// SuccessUserIdsCount 是已成功发送的用户数
+			SuccessUserIdsCount int `json:"success_user_ids_count"`
+			// ReadUserIdsCount 是已读的用户数
+			ReadUserIdsCount int `json:"read_user_ids_count"`
+		} `json:"batch_message_send_progress"`
+
+		RecallProgress struct {
+			// Recall 表示是否已撤回
+			Recall bool `json:"recall"`
+			// RecallCount 是已撤回的消息数
+			RecallCount int `json:"recall_count"`
+		} `json:"batch_message_recall_progress"`
+	} `json:"data"`
+}
+
+// BatchMessageReadUserResult 是查询批量消息推送和阅读人数接口的结果
+type BatchMessageReadUserResult struct {
+	utils.APIResultBase
+
+	Data struct {
+		ReadUser struct {
+			// ReadCount 是已读人数
+			ReadCount string `json:"read_count"`
+			// TotalCount 是推送人数
+			TotalCount string `json:"total_count"`
+		} `json:"read_user"`
+	} `json:"data"`
+}
+
 // Do 调用 api
 func (send BatchSend) Do(ctx context.Context, provider conf.TenantAccessTokenProvider) (*BatchSendResult, error) {
 	if send.Content == nil {
 		return nil, fmt.Errorf("Missing content")
 	}
-	_, ok := send.Content.(*SendTextContent)
-	if !ok {
-		return nil, fmt.Errorf("BatchSend only support text content")
+	if err := validateBatchContent(send.Content); err != nil {
+		return nil, err
 	}
 	send.MsgType = send.Content.SendContentMsgType()
+	if c, ok := send.Content.(cardContent); ok {
+		send.Card = c.sendCard()
+		send.Content = nil
+	}
 	result := &BatchSendResult{}
 	err := utils.PostJSONWithTenantAccessToken(ctx, "/message/v4/batch_send", provider, send, result)
 	if err != nil {
@@ -50,3 +95,59 @@ func (send BatchSend) Do(ctx context.Context, provider conf.TenantAccessTokenPro
 	}
 	return result, nil
 }
+
+// GetBatchMessageProgress 查询批量消息的发送/已读/撤回进度 (im/v1), batchMessageId 是 BatchSendResult 中的 MessageId
+func GetBatchMessageProgress(ctx context.Context, provider conf.TenantAccessTokenProvider, batchMessageId string) (*BatchMessageProgressResult, error) {
+	result := &BatchMessageProgressResult{}
+	err := utils.GetJSONWithTenantAccessToken(ctx, batchMessagePath(batchMessageId)+"/get_progress", provider, nil, result)
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// GetBatchMessageReadUser 查询批量消息的推送和阅读人数 (im/v1), batchMessageId 是 BatchSendResult 中的 MessageId
+func GetBatchMessageReadUser(ctx context.Context, provider conf.TenantAccessTokenProvider, batchMessageId string) (*BatchMessageReadUserResult, error) {
+	result := &BatchMessageReadUserResult{}
+	err := utils.GetJSONWithTenantAccessToken(ctx, batchMessagePath(batchMessageId)+"/read_user", provider, nil, result)
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// RecallBatchMessage 撤回批量消息 (im/v1), 撤回是异步的，进度见 GetBatchMessageProgress
+func RecallBatchMessage(ctx context.Context, provider conf.TenantAccessTokenProvider, batchMessageId string) (*utils.APIResultBase, error) {
+	result := &utils.APIResultBase{}
+	err := utils.DeleteJSONWithTenantAccessToken(ctx, batchMessagePath(batchMessageId), provider, nil, result)
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// validateBatchContent 校验批量发送的内容
+func validateBatchContent(content SendContent) error {
+	switch c := content.(type) {
+	case *SendTextContent:
+		if c.Text == "" {
+			return fmt.Errorf("Empty text")
+		}
+	case *SendImageContent:
+		if c.ImageKey == "" {
+			return fmt.Errorf("Missing image key")
+		}
+	case *SendShareChatContent:
+		if c.ShareOpenChatId == "" {
+			return fmt.Errorf("Missing share chat id")
+		}
+	case *SendPostContent, cardContent:
+	default:
+		return fmt.Errorf("BatchSend does not support %s content", content.SendContentMsgType())
+	}
+	return validateContent(content)
+}
+
+func batchMessagePath(batchMessageId string) string {
+	return "/im/v1/batch_messages/" + url.PathEscape(batchMessageId)
+}
@@ -0,0 +1,115 @@
+package message
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/huangjunwen/feishu-driver/conf"
+	"github.com/huangjunwen/feishu-driver/message/card"
+	"github.com/huangjunwen/feishu-driver/utils"
+)
+
+func TestBatchSend(t *testing.T) {
+	assert := assert.New(t)
+
+	srv, reqs := newIMTestServer(func(r *http.Request) string {
+		switch r.URL.Path {
+		case "/message/v4/batch_send":
+			return `{"code": 0, "data": {"message_id": "bm_1", "invalid_open_ids": ["ou_x"]}}`
+		case "/im/v1/batch_messages/bm_1/get_progress":
+			return `{"code": 0, "data": {
+				"batch_message_send_progress": {"valid_user_ids_count": 10, "success_user_ids_count": 9, "read_user_ids_count": 3},
+				"batch_message_recall_progress": {"recall": true, "recall_count": 2}
+			}}`
+		case "/im/v1/batch_messages/bm_1/read_user":
+			return `{"code": 0, "data": {"read_user": {"read_count": "3", "total_count": "10"}}}`
+		}
+		return `{"code": 0, "msg": "success"}`
+	})
+	defer srv.Close()
+	ctx := utils.APIOptions{URLBase: srv.URL}.WithCtx(context.Background())
+	provider := conf.TenantAccessTokenProviderFunc(func() (string, error) { return "t-token", nil })
+	last := func() recordedRequest { return (*reqs)[len(*reqs)-1] }
+
+	// 各类内容的校验
+	for _, content := range []SendContent{
+		&SendTextContent{},
+		&SendImageContent{},
+		&SendShareChatContent{},
+		&SendPostContent{},
+		&SendCardContent{},
+	} {
+		_, err := BatchSend{OpenIds: []string{"ou_1"}, Content: content}.Do(ctx, provider)
+		assert.Error(err, content.SendContentMsgType())
+	}
+	assert.Len(*reqs, 0)
+
+	// 富文本
+	{
+		post, err := NewPostBuilder().Title("公告").Text("放假").Build()
+		assert.NoError(err)
+		res, err := BatchSend{
+			DepartmentIds: []string{"od_1"},
+			Content:       post,
+		}.Do(ctx, provider)
+		assert.NoError(err)
+		assert.Equal("bm_1", res.Data.MessageId)
+		assert.Equal([]string{"ou_x"}, res.Data.InvalidOpenIds)
+		assert.JSONEq(`{
+			"department_ids": ["od_1"],
+			"open_ids": null,
+			"user_ids": null,
+			"msg_type": "post",
+			"content": {"post": {"zh_cn": {"title": "公告", "content": [[{"tag": "text", "text": "放假"}]]}}}
+		}`, last().Body)
+	}
+
+	// 卡片
+	{
+		_, err := BatchSend{
+			OpenIds: []string{"ou_1"},
+			Content: &SendCardContent{Card: card.New().WithTitle("公告", "").Add(card.NewMarkdown("放假"))},
+		}.Do(ctx, provider)
+		assert.NoError(err)
+		assert.JSONEq(`{
+			"department_ids": null,
+			"open_ids": ["ou_1"],
+			"user_ids": null,
+			"msg_type": "interactive",
+			"card": {
+				"config": {"wide_screen_mode": true, "enable_forward": true},
+				"header": {"title": {"tag": "plain_text", "content": "公告"}},
+				"elements": [{"tag": "markdown", "content": "放假"}]
+			}
+		}`, last().Body)
+	}
+
+	// 进度
+	{
+		res, err := GetBatchMessageProgress(ctx, provider, "bm_1")
+		assert.NoError(err)
+		assert.Equal(9, res.Data.SendProgress.SuccessUserIdsCount)
+		assert.Equal(3, res.Data.SendProgress.ReadUserIdsCount)
+		assert.True(res.Data.RecallProgress.Recall)
+	}
+
+	// 已读人数
+	{
+		res, err := GetBatchMessageReadUser(ctx, provider, "bm_1")
+		assert.NoError(err)
+		assert.Equal("3", res.Data.ReadUser.ReadCount)
+		assert.Equal("10", res.Data.ReadUser.TotalCount)
+	}
+
+	// 撤回
+	{
+		res, err := RecallBatchMessage(ctx, provider, "bm_1")
+		assert.NoError(err)
+		assert.NoError(res.ResultError())
+		assert.Equal("DELETE", last().Method)
+		assert.Equal("/im/v1/batch_messages/bm_1", last().URI)
+	}
+}
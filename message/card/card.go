@@ -0,0 +1,284 @@
+package card
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"sort"
+)
+
+var (
+	// Locales 是卡片支持的语言
+	Locales = []string{"zh_cn", "en_us", "ja_jp"}
+
+	// Templates 是标题栏支持的颜色
+	Templates = []string{"blue", "wathet", "turquoise", "green", "yellow", "orange", "red", "carmine", "violet", "purple", "indigo", "grey"}
+)
+
+var (
+	// TextTagPlain 是纯文本
+	TextTagPlain = "plain_text"
+
+	// TextTagLarkMd 是 lark_md 格式的文本
+	TextTagLarkMd = "lark_md"
+)
+
+// Card 是消息卡片
+type Card struct {
+	Config   *Config `json:"config,omitempty"`
+	Header   *Header `json:"header,omitempty"`
+	CardLink *URL    `json:"card_link,omitempty"`
+
+	// Elements 是卡片内容
+	Elements []Element `json:"elements,omitempty"`
+
+	// I18nElements 是按语言区分的卡片内容, 与 Elements 二选一
+	I18nElements map[string][]Element `json:"i18n_elements,omitempty"`
+}
+
+// Config 是卡片配置
+type Config struct {
+	// WideScreenMode 表示是否根据屏幕宽度动态调整卡片宽度
+	WideScreenMode bool `json:"wide_screen_mode"`
+
+	// EnableForward 表示是否允许转发
+	EnableForward bool `json:"enable_forward"`
+
+	// UpdateMulti 表示是否为共享卡片 (更新后所有接收者可见)
+	UpdateMulti bool `json:"update_multi,omitempty"`
+}
+
+// Header 是卡片标题栏
+type Header struct {
+	Title *Text `json:"title"`
+
+	// Template 是标题栏颜色, 见 Templates
+	Template string `json:"template,omitempty"`
+}
+
+// URL 是按平台区分的链接
+type URL struct {
+	URL        string `json:"url,omitempty"`
+	AndroidURL string `json:"android_url,omitempty"`
+	IOSURL     string `json:"ios_url,omitempty"`
+	PCURL      string `json:"pc_url,omitempty"`
+}
+
+// Text 是文本, 可作为 note 的元素
+type Text struct {
+	// Tag 是文本类型: TextTagPlain/TextTagLarkMd
+	Tag string `json:"tag"`
+
+	Content string `json:"content"`
+
+	// Lines 是最大显示行数
+	Lines int `json:"lines,omitempty"`
+
+	// I18n 是按语言区分的内容
+	I18n map[string]string `json:"i18n,omitempty"`
+}
+
+// Element 是卡片中的元素
+type Element interface {
+	// ElementTag 返回元素的 tag
+	ElementTag() string
+}
+
+// validator 由需要校验的元素实现
+type validator interface {
+	Validate() error
+}
+
+// New 创建一个卡片, 默认开启宽屏模式及允许转发
+func New() *Card {
+	return &Card{
+		Config: &Config{
+			WideScreenMode: true,
+			EnableForward:  true,
+		},
+	}
+}
+
+// PlainText 创建纯文本
+func PlainText(content string) *Text {
+	return &Text{Tag: TextTagPlain, Content: content}
+}
+
+// LarkMd 创建 lark_md 格式的文本
+func LarkMd(content string) *Text {
+	return &Text{Tag: TextTagLarkMd, Content: content}
+}
+
+// WithI18n 设置按语言区分的内容
+func (t *Text) WithI18n(i18n map[string]string) *Text {
+	t.I18n = i18n
+	return t
+}
+
+// WithConfig 设置卡片配置
+func (c *Card) WithConfig(config *Config) *Card {
+	c.Config = config
+	return c
+}
+
+// UpdateMulti 设置为共享卡片
+func (c *Card) UpdateMulti() *Card {
+	if c.Config == nil {
+		c.Config = &Config{}
+	}
+	c.Config.UpdateMulti = true
+	return c
+}
+
+// WithHeader 设置标题及标题栏颜色 (见 Templates), template 可为空
+func (c *Card) WithHeader(title *Text, template string) *Card {
+	c.Header = &Header{
+		Title:    title,
+		Template: template,
+	}
+	return c
+}
+
+// WithTitle 设置纯文本标题及标题栏颜色
+func (c *Card) WithTitle(title, template string) *Card {
+	return c.WithHeader(PlainText(title), template)
+}
+
+// WithLink 设置点击卡片时跳转的链接
+func (c *Card) WithLink(link *URL) *Card {
+	c.CardLink = link
+	return c
+}
+
+// Add 添加元素
+func (c *Card) Add(elems ...Element) *Card {
+	c.Elements = append(c.Elements, elems...)
+	return c
+}
+
+// AddI18n 添加语言 locale 的元素
+func (c *Card) AddI18n(locale string, elems ...Element) *Card {
+	if c.I18nElements == nil {
+		c.I18nElements = map[string][]Element{}
+	}
+	c.I18nElements[locale] = append(c.I18nElements[locale], elems...)
+	return c
+}
+
+// Validate 校验卡片
+func (c *Card) Validate() error {
+	if len(c.Elements) != 0 && len(c.I18nElements) != 0 {
+		return fmt.Errorf("Card can't have both elements and i18n_elements")
+	}
+	if len(c.Elements) == 0 && len(c.I18nElements) == 0 {
+		return fmt.Errorf("Card has no elements")
+	}
+
+	if c.Header != nil {
+		if c.Header.Title == nil {
+			return fmt.Errorf("Card header missing title")
+		}
+		if err := c.Header.Title.Validate(); err != nil {
+			return fmt.Errorf("Card header: %s", err)
+		}
+		if c.Header.Template != "" && !contains(Templates, c.Header.Template) {
+			return fmt.Errorf("Unsupported card header template %q", c.Header.Template)
+		}
+	}
+
+	if err := validateElements(c.Elements); err != nil {
+		return err
+	}
+
+	locales := make([]string, 0, len(c.I18nElements))
+	for locale := range c.I18nElements {
+		locales = append(locales, locale)
+	}
+	sort.Strings(locales)
+	for _, locale := range locales {
+		if !contains(Locales, locale) {
+			return fmt.Errorf("Unsupported card locale %q", locale)
+		}
+		if err := validateElements(c.I18nElements[locale]); err != nil {
+			return fmt.Errorf("Locale %q: %s", locale, err)
+		}
+	}
+	return nil
+}
+
+// ElementTag 返回 plain_text 或 lark_md
+func (t *Text) ElementTag() string {
+	return t.Tag
+}
+
+// Validate 校验文本
+func (t *Text) Validate() error {
+	if t.Tag != TextTagPlain && t.Tag != TextTagLarkMd {
+		return fmt.Errorf("Unsupported text tag %q", t.Tag)
+	}
+	if t.Content == "" && len(t.I18n) == 0 {
+		return fmt.Errorf("Empty text")
+	}
+	return nil
+}
+
+func validateElements(elems []Element) error {
+	for i, elem := range elems {
+		if _, ok := elem.(*Text); ok {
+			return fmt.Errorf("Element %d: text can only be used in note", i)
+		}
+		if err := validateElement(elem); err != nil {
+			return fmt.Errorf("Element %d: %s", i, err)
+		}
+	}
+	return nil
+}
+
+func validateElement(elem Element) error {
+	if elem == nil {
+		return fmt.Errorf("nil element")
+	}
+	if v, ok := elem.(validator); ok {
+		return v.Validate()
+	}
+	return nil
+}
+
+func validateText(name string, t *Text, required bool) error {
+	if t == nil {
+		if required {
+			return fmt.Errorf("%s missing", name)
+		}
+		return nil
+	}
+	if err := t.Validate(); err != nil {
+		return fmt.Errorf("%s: %s", name, err)
+	}
+	return nil
+}
+
+// marshalWithTag 将 v 编码为 json 对象并在开头加上 "tag" 字段
+func marshalWithTag(tag string, v interface{}) ([]byte, error) {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	tagField, _ := json.Marshal(tag)
+	buf := &bytes.Buffer{}
+	buf.WriteString(`{"tag":`)
+	buf.Write(tagField)
+	if len(b) > 2 {
+		buf.WriteByte(',')
+	}
+	buf.Write(b[1:])
+	return buf.Bytes(), nil
+}
+
+func contains(strs []string, s string) bool {
+	for _, str := range strs {
+		if str == s {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,162 @@
+package card
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCard(t *testing.T) {
+	assert := assert.New(t)
+
+	c := New().
+		WithTitle("服务告警", "red").
+		WithLink(&URL{URL: "https://example.com"}).
+		Add(
+			NewDiv(LarkMd("**svc** 错误率过高"),
+				NewField(true, LarkMd("**环境**\nprod")),
+				NewField(true, LarkMd("**错误率**\n5%")),
+			).WithExtra(NewImg("img_1", "趋势")),
+			NewMarkdown("[详情]($urlVal)").WithHref("urlVal", &URL{URL: "https://example.com/a", PCURL: "https://example.com/pc"}),
+			NewHr(),
+			NewColumnSet(
+				NewColumn(1, NewMarkdown("左")),
+				NewColumn(0, NewMarkdown("右")),
+			),
+			NewAction(
+				NewButton("确认").Primary().WithValue(map[string]string{"op": "ack"}).WithConfirm("确认告警", "确认后不再提醒"),
+				NewButton("查看").WithMultiURL(&URL{URL: "https://example.com", PCURL: "https://example.com/pc"}),
+				NewSelectStatic("静默", NewOption("1 小时", "1h"), NewOption("1 天", "1d")).WithValue(map[string]string{"op": "mute"}),
+				NewOverflow(&Option{Text: PlainText("文档"), Value: "doc", URL: "https://example.com/doc"}),
+				NewDatePicker("2021-01-01").WithValue(map[string]string{"op": "date"}),
+				NewTimePicker("10:00"),
+			).WithLayout("flow"),
+			NewNote(PlainText("来自监控系统"), NewImg("img_2", "logo")),
+			Raw(`{"tag": "custom", "x": 1}`),
+		)
+	assert.NoError(c.Validate())
+
+	b, err := json.Marshal(c)
+	assert.NoError(err)
+	assert.JSONEq(`{
+		"config": {"wide_screen_mode": true, "enable_forward": true},
+		"header": {"title": {"tag": "plain_text", "content": "服务告警"}, "template": "red"},
+		"card_link": {"url": "https://example.com"},
+		"elements": [
+			{
+				"tag": "div",
+				"text": {"tag": "lark_md", "content": "**svc** 错误率过高"},
+				"fields": [
+					{"is_short": true, "text": {"tag": "lark_md", "content": "**环境**\nprod"}},
+					{"is_short": true, "text": {"tag": "lark_md", "content": "**错误率**\n5%"}}
+				],
+				"extra": {"tag": "img", "img_key": "img_1", "alt": {"tag": "plain_text", "content": "趋势"}}
+			},
+			{
+				"tag": "markdown",
+				"content": "[详情]($urlVal)",
+				"href": {"urlVal": {"url": "https://example.com/a", "pc_url": "https://example.com/pc"}}
+			},
+			{"tag": "hr"},
+			{
+				"tag": "column_set",
+				"columns": [
+					{"tag": "column", "width": "weighted", "weight": 1, "elements": [{"tag": "markdown", "content": "左"}]},
+					{"tag": "column", "width": "auto", "elements": [{"tag": "markdown", "content": "右"}]}
+				]
+			},
+			{
+				"tag": "action",
+				"layout": "flow",
+				"actions": [
+					{
+						"tag": "button",
+						"text": {"tag": "plain_text", "content": "确认"},
+						"type": "primary",
+						"value": {"op": "ack"},
+						"confirm": {
+							"title": {"tag": "plain_text", "content": "确认告警"},
+							"text": {"tag": "plain_text", "content": "确认后不再提醒"}
+						}
+					},
+					{
+						"tag": "button",
+						"text": {"tag": "plain_text", "content": "查看"},
+						"multi_url": {"url": "https://example.com", "pc_url": "https://example.com/pc"}
+					},
+					{
+						"tag": "select_static",
+						"placeholder": {"tag": "plain_text", "content": "静默"},
+						"options": [
+							{"text": {"tag": "plain_text", "content": "1 小时"}, "value": "1h"},
+							{"text": {"tag": "plain_text", "content": "1 天"}, "value": "1d"}
+						],
+						"value": {"op": "mute"}
+					},
+					{
+						"tag": "overflow",
+						"options": [{"text": {"tag": "plain_text", "content": "文档"}, "value": "doc", "url": "https://example.com/doc"}]
+					},
+					{"tag": "date_picker", "initial_date": "2021-01-01", "value": {"op": "date"}},
+					{"tag": "picker_time", "initial_time": "10:00"}
+				]
+			},
+			{
+				"tag": "note",
+				"elements": [
+					{"tag": "plain_text", "content": "来自监控系统"},
+					{"tag": "img", "img_key": "img_2", "alt": {"tag": "plain_text", "content": "logo"}}
+				]
+			},
+			{"tag": "custom", "x": 1}
+		]
+	}`, string(b))
+
+	// i18n
+	{
+		c := New().
+			WithHeader(PlainText("告警").WithI18n(map[string]string{"zh_cn": "告警", "en_us": "Alert"}), "").
+			UpdateMulti().
+			AddI18n("zh_cn", NewMarkdown("错误")).
+			AddI18n("en_us", NewMarkdown("Error"))
+		assert.NoError(c.Validate())
+		b, err := json.Marshal(c)
+		assert.NoError(err)
+		assert.JSONEq(`{
+			"config": {"wide_screen_mode": true, "enable_forward": true, "update_multi": true},
+			"header": {"title": {"tag": "plain_text", "content": "告警", "i18n": {"zh_cn": "告警", "en_us": "Alert"}}},
+			"i18n_elements": {
+				"zh_cn": [{"tag": "markdown", "content": "错误"}],
+				"en_us": [{"tag": "markdown", "content": "Error"}]
+			}
+		}`, string(b))
+	}
+
+	// 校验
+	for i, c := range []*Card{
+		New(),
+		New().Add(NewHr()).AddI18n("zh_cn", NewHr()),
+		New().AddI18n("fr_fr", NewHr()),
+		New().WithTitle("t", "pink").Add(NewHr()),
+		New().WithTitle("", "").Add(NewHr()),
+		New().Add(NewDiv(nil)),
+		New().Add(NewDiv(PlainText("a")).WithExtra(NewHr())),
+		New().Add(NewMarkdown("")),
+		New().Add(NewImg("", "a")),
+		New().Add(NewNote(NewHr())),
+		New().Add(NewColumnSet()),
+		New().Add(NewColumnSet(NewColumn(1, NewMarkdown("")))),
+		New().Add(NewAction()),
+		New().Add(NewAction(NewButton("a").WithURL("u").WithMultiURL(&URL{}))),
+		New().Add(NewAction(NewSelectStatic("a"))),
+		New().Add(NewAction(NewOverflow(NewOption("", "v")))),
+		New().Add(NewAction(NewButton("a").WithConfirm("", "b"))),
+		New().Add(NewAction(&DatePicker{Tag: "xxx"})),
+		New().Add(Raw(`xxx`)),
+		New().Add(PlainText("a")),
+		New().Add(NewNote(&Text{Tag: "xxx", Content: "a"})),
+	} {
+		assert.Error(c.Validate(), "case %d", i)
+	}
+}
@@ -0,0 +1,319 @@
+package card
+
+import (
+	"fmt"
+)
+
+var (
+	// ButtonTypeDefault 是默认样式按钮
+	ButtonTypeDefault = "default"
+
+	// ButtonTypePrimary 是强调样式按钮
+	ButtonTypePrimary = "primary"
+
+	// ButtonTypeDanger 是警示样式按钮
+	ButtonTypeDanger = "danger"
+)
+
+// ActionElement 是交互组件: *Button/*SelectStatic/*Overflow/*DatePicker, 可放在 Action 中
+type ActionElement interface {
+	Element
+
+	actionElement()
+}
+
+// Button 是按钮
+type Button struct {
+	Text *Text `json:"text"`
+
+	// URL/MultiURL 是点击后跳转的链接, 二选一
+	URL      string `json:"url,omitempty"`
+	MultiURL *URL   `json:"multi_url,omitempty"`
+
+	// Type 是按钮样式: ButtonTypeDefault/ButtonTypePrimary/ButtonTypeDanger
+	Type string `json:"type,omitempty"`
+
+	// Value 是点击后回传的数据, 需编码为 json 对象
+	Value interface{} `json:"value,omitempty"`
+
+	Confirm *Confirm `json:"confirm,omitempty"`
+}
+
+// SelectStatic 是下拉菜单
+type SelectStatic struct {
+	Placeholder   *Text       `json:"placeholder,omitempty"`
+	InitialOption string      `json:"initial_option,omitempty"`
+	Options       []*Option   `json:"options"`
+	Value         interface{} `json:"value,omitempty"`
+	Confirm       *Confirm    `json:"confirm,omitempty"`
+}
+
+// Overflow 是折叠按钮组
+type Overflow struct {
+	Options []*Option   `json:"options"`
+	Value   interface{} `json:"value,omitempty"`
+	Confirm *Confirm    `json:"confirm,omitempty"`
+}
+
+// Option 是 SelectStatic/Overflow 中的选项
+type Option struct {
+	Text     *Text  `json:"text"`
+	Value    string `json:"value"`
+	URL      string `json:"url,omitempty"`
+	MultiURL *URL   `json:"multi_url,omitempty"`
+}
+
+// DatePicker 是日期/时间/日期时间选择器, Tag 为 date_picker/picker_time/picker_datetime
+type DatePicker struct {
+	Tag string `json:"-"`
+
+	// InitialDate/InitialTime/InitialDatetime 是初始值, 格式分别为 yyyy-MM-dd/HH:mm/yyyy-MM-dd HH:mm
+	InitialDate     string `json:"initial_date,omitempty"`
+	InitialTime     string `json:"initial_time,omitempty"`
+	InitialDatetime string `json:"initial_datetime,omitempty"`
+
+	Placeholder *Text       `json:"placeholder,omitempty"`
+	Value       interface{} `json:"value,omitempty"`
+	Confirm     *Confirm    `json:"confirm,omitempty"`
+}
+
+// Confirm 是二次确认弹框
+type Confirm struct {
+	Title *Text `json:"title"`
+	Text  *Text `json:"text"`
+}
+
+// NewButton 创建按钮
+func NewButton(text string) *Button {
+	return &Button{Text: PlainText(text)}
+}
+
+// Primary 设置为强调样式
+func (e *Button) Primary() *Button {
+	e.Type = ButtonTypePrimary
+	return e
+}
+
+// Danger 设置为警示样式
+func (e *Button) Danger() *Button {
+	e.Type = ButtonTypeDanger
+	return e
+}
+
+// WithURL 设置跳转链接
+func (e *Button) WithURL(url string) *Button {
+	e.URL = url
+	return e
+}
+
+// WithMultiURL 设置按平台区分的跳转链接
+func (e *Button) WithMultiURL(url *URL) *Button {
+	e.MultiURL = url
+	return e
+}
+
+// WithValue 设置回传数据
+func (e *Button) WithValue(value interface{}) *Button {
+	e.Value = value
+	return e
+}
+
+// WithConfirm 设置二次确认弹框
+func (e *Button) WithConfirm(title, text string) *Button {
+	e.Confirm = NewConfirm(title, text)
+	return e
+}
+
+// NewSelectStatic 创建下拉菜单
+func NewSelectStatic(placeholder string, options ...*Option) *SelectStatic {
+	return &SelectStatic{Placeholder: PlainText(placeholder), Options: options}
+}
+
+// WithValue 设置回传数据
+func (e *SelectStatic) WithValue(value interface{}) *SelectStatic {
+	e.Value = value
+	return e
+}
+
+// WithConfirm 设置二次确认弹框
+func (e *SelectStatic) WithConfirm(title, text string) *SelectStatic {
+	e.Confirm = NewConfirm(title, text)
+	return e
+}
+
+// NewOverflow 创建折叠按钮组
+func NewOverflow(options ...*Option) *Overflow {
+	return &Overflow{Options: options}
+}
+
+// WithValue 设置回传数据
+func (e *Overflow) WithValue(value interface{}) *Overflow {
+	e.Value = value
+	return e
+}
+
+// WithConfirm 设置二次确认弹框
+func (e *Overflow) WithConfirm(title, text string) *Overflow {
+	e.Confirm = NewConfirm(title, text)
+	return e
+}
+
+// NewOption 创建选项
+func NewOption(text, value string) *Option {
+	return &Option{Text: PlainText(text), Value: value}
+}
+
+// NewDatePicker 创建日期选择器
+func NewDatePicker(initialDate string) *DatePicker {
+	return &DatePicker{Tag: "date_picker", InitialDate: initialDate}
+}
+
+// NewTimePicker 创建时间选择器
+func NewTimePicker(initialTime string) *DatePicker {
+	return &DatePicker{Tag: "picker_time", InitialTime: initialTime}
+}
+
+// NewDatetimePicker 创建日期时间选择器
+func NewDatetimePicker(initialDatetime string) *DatePicker {
+	return &DatePicker{Tag: "picker_datetime", InitialDatetime: initialDatetime}
+}
+
+// WithValue 设置回传数据
+func (e *DatePicker) WithValue(value interface{}) *DatePicker {
+	e.Value = value
+	return e
+}
+
+// WithConfirm 设置二次确认弹框
+func (e *DatePicker) WithConfirm(title, text string) *DatePicker {
+	e.Confirm = NewConfirm(title, text)
+	return e
+}
+
+// NewConfirm 创建二次确认弹框
+func NewConfirm(title, text string) *Confirm {
+	return &Confirm{Title: PlainText(title), Text: PlainText(text)}
+}
+
+// Validate 校验
+func (e *Button) Validate() error {
+	if err := validateText("button text", e.Text, true); err != nil {
+		return err
+	}
+	if e.URL != "" && e.MultiURL != nil {
+		return fmt.Errorf("button can't have both url and multi_url")
+	}
+	if e.Type != "" && e.Type != ButtonTypeDefault && e.Type != ButtonTypePrimary && e.Type != ButtonTypeDanger {
+		return fmt.Errorf("Unsupported button type %q", e.Type)
+	}
+	return e.Confirm.validate()
+}
+
+// Validate 校验
+func (e *SelectStatic) Validate() error {
+	if err := validateOptions(e.Options); err != nil {
+		return err
+	}
+	if err := validateText("select_static placeholder", e.Placeholder, false); err != nil {
+		return err
+	}
+	return e.Confirm.validate()
+}
+
+// Validate 校验
+func (e *Overflow) Validate() error {
+	if err := validateOptions(e.Options); err != nil {
+		return err
+	}
+	return e.Confirm.validate()
+}
+
+// Validate 校验
+func (e *DatePicker) Validate() error {
+	switch e.Tag {
+	case "date_picker", "picker_time", "picker_datetime":
+	default:
+		return fmt.Errorf("Unsupported date picker tag %q", e.Tag)
+	}
+	if err := validateText("date picker placeholder", e.Placeholder, false); err != nil {
+		return err
+	}
+	return e.Confirm.validate()
+}
+
+func (c *Confirm) validate() error {
+	if c == nil {
+		return nil
+	}
+	if err := validateText("confirm title", c.Title, true); err != nil {
+		return err
+	}
+	return validateText("confirm text", c.Text, true)
+}
+
+func validateOptions(options []*Option) error {
+	if len(options) == 0 {
+		return fmt.Errorf("Empty options")
+	}
+	for i, option := range options {
+		if option == nil {
+			return fmt.Errorf("option %d is nil", i)
+		}
+		if err := validateText(fmt.Sprintf("option %d", i), option.Text, true); err != nil {
+			return err
+		}
+		if option.URL != "" && option.MultiURL != nil {
+			return fmt.Errorf("option %d can't have both url and multi_url", i)
+		}
+	}
+	return nil
+}
+
+// ElementTag 返回 button
+func (e *Button) ElementTag() string {
+	return "button"
+}
+
+// ElementTag 返回 select_static
+func (e *SelectStatic) ElementTag() string {
+	return "select_static"
+}
+
+// ElementTag 返回 overflow
+func (e *Overflow) ElementTag() string {
+	return "overflow"
+}
+
+// ElementTag 返回 e.Tag (date_picker/picker_time/picker_datetime)
+func (e *DatePicker) ElementTag() string {
+	return e.Tag
+}
+
+func (e *Button) actionElement()       {}
+func (e *SelectStatic) actionElement() {}
+func (e *Overflow) actionElement()     {}
+func (e *DatePicker) actionElement()   {}
+
+// MarshalJSON 编码时加上 tag
+func (e *Button) MarshalJSON() ([]byte, error) {
+	type button Button
+	return marshalWithTag(e.ElementTag(), (*button)(e))
+}
+
+// MarshalJSON 编码时加上 tag
+func (e *SelectStatic) MarshalJSON() ([]byte, error) {
+	type selectStatic SelectStatic
+	return marshalWithTag(e.ElementTag(), (*selectStatic)(e))
+}
+
+// MarshalJSON 编码时加上 tag
+func (e *Overflow) MarshalJSON() ([]byte, error) {
+	type overflow Overflow
+	return marshalWithTag(e.ElementTag(), (*overflow)(e))
+}
+
+// MarshalJSON 编码时加上 tag
+func (e *DatePicker) MarshalJSON() ([]byte, error) {
+	type datePicker DatePicker
+	return marshalWithTag(e.ElementTag(), (*datePicker)(e))
+}
@@ -0,0 +1,3 @@
+// Package card 是消息卡片 (interactive) 的类型化模型及构建方法, 构建的 *Card 可用于 message.SendCardContent,
+// 也可作为 webhook.CardActionHandler 返回的新卡片
+package card
@@ -0,0 +1,363 @@
+package card
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// Div 是内容模块
+type Div struct {
+	Text   *Text    `json:"text,omitempty"`
+	Fields []*Field `json:"fields,omitempty"`
+
+	// Extra 是附加元素, 可以是 *Img/*Button/*SelectStatic/*Overflow/*DatePicker
+	Extra Element `json:"extra,omitempty"`
+}
+
+// Field 是 Div 中的字段
+type Field struct {
+	// IsShort 表示是否并排布局
+	IsShort bool  `json:"is_short"`
+	Text    *Text `json:"text"`
+}
+
+// Markdown 是 markdown 模块
+type Markdown struct {
+	Content string `json:"content"`
+
+	// Href 是差异化跳转链接, 在 Content 中以 [文本]($urlVal) 引用
+	Href map[string]*URL `json:"href,omitempty"`
+}
+
+// Hr 是分割线
+type Hr struct{}
+
+// Img 是图片模块
+type Img struct {
+	ImgKey string `json:"img_key"`
+	Alt    *Text  `json:"alt"`
+	Title  *Text  `json:"title,omitempty"`
+
+	// Mode 是图片显示模式: crop_center/fit_horizontal
+	Mode string `json:"mode,omitempty"`
+
+	// Preview 表示点击后是否放大图片, nil 表示默认 (放大)
+	Preview *bool `json:"preview,omitempty"`
+}
+
+// Note 是备注模块, Elements 只能是 *Text 或 *Img
+type Note struct {
+	Elements []Element `json:"elements"`
+}
+
+// ColumnSet 是多列布局
+type ColumnSet struct {
+	// FlexMode 是移动端窄屏时的自适应方式: none/stretch/flow/bisect/trisect
+	FlexMode string `json:"flex_mode,omitempty"`
+
+	// BackgroundStyle 是背景色: default/grey
+	BackgroundStyle string `json:"background_style,omitempty"`
+
+	Columns []*Column `json:"columns"`
+}
+
+// Column 是多列布局中的一列
+type Column struct {
+	// Width 是列宽: auto/weighted
+	Width string `json:"width,omitempty"`
+
+	// Weight 是 Width 为 weighted 时的权重
+	Weight int `json:"weight,omitempty"`
+
+	// VerticalAlign 是垂直对齐方式: top/center/bottom
+	VerticalAlign string `json:"vertical_align,omitempty"`
+
+	Elements []Element `json:"elements"`
+}
+
+// Action 是交互模块
+type Action struct {
+	Actions []ActionElement `json:"actions"`
+
+	// Layout 是布局方式: bisected/trisection/flow
+	Layout string `json:"layout,omitempty"`
+}
+
+// Raw 是原始的 json 元素, 用于尚未支持的元素类型
+type Raw json.RawMessage
+
+// NewDiv 创建内容模块
+func NewDiv(text *Text, fields ...*Field) *Div {
+	return &Div{Text: text, Fields: fields}
+}
+
+// WithExtra 设置附加元素
+func (e *Div) WithExtra(extra Element) *Div {
+	e.Extra = extra
+	return e
+}
+
+// NewField 创建 Div 中的字段
+func NewField(isShort bool, text *Text) *Field {
+	return &Field{IsShort: isShort, Text: text}
+}
+
+// NewMarkdown 创建 markdown 模块
+func NewMarkdown(content string) *Markdown {
+	return &Markdown{Content: content}
+}
+
+// WithHref 添加差异化跳转链接
+func (e *Markdown) WithHref(name string, url *URL) *Markdown {
+	if e.Href == nil {
+		e.Href = map[string]*URL{}
+	}
+	e.Href[name] = url
+	return e
+}
+
+// NewHr 创建分割线
+func NewHr() *Hr {
+	return &Hr{}
+}
+
+// NewImg 创建图片模块
+func NewImg(imgKey, alt string) *Img {
+	return &Img{ImgKey: imgKey, Alt: PlainText(alt)}
+}
+
+// NewNote 创建备注模块
+func NewNote(elems ...Element) *Note {
+	return &Note{Elements: elems}
+}
+
+// NewColumnSet 创建多列布局
+func NewColumnSet(columns ...*Column) *ColumnSet {
+	return &ColumnSet{Columns: columns}
+}
+
+// NewColumn 创建一列, 权重为 weight (小于等于 0 时列宽为 auto)
+func NewColumn(weight int, elems ...Element) *Column {
+	if weight <= 0 {
+		return &Column{Width: "auto", Elements: elems}
+	}
+	return &Column{Width: "weighted", Weight: weight, Elements: elems}
+}
+
+// NewAction 创建交互模块
+func NewAction(actions ...ActionElement) *Action {
+	return &Action{Actions: actions}
+}
+
+// WithLayout 设置布局方式
+func (e *Action) WithLayout(layout string) *Action {
+	e.Layout = layout
+	return e
+}
+
+// Validate 校验
+func (e *Div) Validate() error {
+	if e.Text == nil && len(e.Fields) == 0 {
+		return fmt.Errorf("div must have text or fields")
+	}
+	if err := validateText("div text", e.Text, false); err != nil {
+		return err
+	}
+	for i, field := range e.Fields {
+		if field == nil {
+			return fmt.Errorf("div field %d is nil", i)
+		}
+		if err := validateText(fmt.Sprintf("div field %d", i), field.Text, true); err != nil {
+			return err
+		}
+	}
+	if e.Extra != nil {
+		switch e.Extra.(type) {
+		case *Img, *Button, *SelectStatic, *Overflow, *DatePicker, Raw:
+		default:
+			return fmt.Errorf("div extra can't be %s", e.Extra.ElementTag())
+		}
+		if err := validateElement(e.Extra); err != nil {
+			return fmt.Errorf("div extra: %s", err)
+		}
+	}
+	return nil
+}
+
+// Validate 校验
+func (e *Markdown) Validate() error {
+	if e.Content == "" {
+		return fmt.Errorf("Empty markdown content")
+	}
+	return nil
+}
+
+// Validate 校验
+func (e *Img) Validate() error {
+	if e.ImgKey == "" {
+		return fmt.Errorf("img missing img_key")
+	}
+	if err := validateText("img alt", e.Alt, true); err != nil {
+		return err
+	}
+	return validateText("img title", e.Title, false)
+}
+
+// Validate 校验
+func (e *Note) Validate() error {
+	if len(e.Elements) == 0 {
+		return fmt.Errorf("Empty note")
+	}
+	for i, elem := range e.Elements {
+		switch elem.(type) {
+		case *Text, *Img:
+		default:
+			return fmt.Errorf("note element %d must be text or img", i)
+		}
+		if err := validateElement(elem); err != nil {
+			return fmt.Errorf("note element %d: %s", i, err)
+		}
+	}
+	return nil
+}
+
+// Validate 校验
+func (e *ColumnSet) Validate() error {
+	if len(e.Columns) == 0 {
+		return fmt.Errorf("Empty column_set")
+	}
+	for i, column := range e.Columns {
+		if column == nil {
+			return fmt.Errorf("column %d is nil", i)
+		}
+		if err := validateElements(column.Elements); err != nil {
+			return fmt.Errorf("column %d: %s", i, err)
+		}
+	}
+	return nil
+}
+
+// Validate 校验
+func (e *Action) Validate() error {
+	if len(e.Actions) == 0 {
+		return fmt.Errorf("Empty action")
+	}
+	for i, action := range e.Actions {
+		if err := validateElement(action); err != nil {
+			return fmt.Errorf("action %d: %s", i, err)
+		}
+	}
+	return nil
+}
+
+// ElementTag 返回 div
+func (e *Div) ElementTag() string {
+	return "div"
+}
+
+// ElementTag 返回 markdown
+func (e *Markdown) ElementTag() string {
+	return "markdown"
+}
+
+// ElementTag 返回 hr
+func (e *Hr) ElementTag() string {
+	return "hr"
+}
+
+// ElementTag 返回 img
+func (e *Img) ElementTag() string {
+	return "img"
+}
+
+// ElementTag 返回 note
+func (e *Note) ElementTag() string {
+	return "note"
+}
+
+// ElementTag 返回 column_set
+func (e *ColumnSet) ElementTag() string {
+	return "column_set"
+}
+
+// ElementTag 返回 column
+func (e *Column) ElementTag() string {
+	return "column"
+}
+
+// ElementTag 返回 action
+func (e *Action) ElementTag() string {
+	return "action"
+}
+
+// ElementTag 返回 json 中的 tag 字段
+func (e Raw) ElementTag() string {
+	tag := &struct {
+		Tag string `json:"tag"`
+	}{}
+	json.Unmarshal(e, tag)
+	return tag.Tag
+}
+
+// MarshalJSON 编码时加上 tag
+func (e *Div) MarshalJSON() ([]byte, error) {
+	type div Div
+	return marshalWithTag(e.ElementTag(), (*div)(e))
+}
+
+// MarshalJSON 编码时加上 tag
+func (e *Markdown) MarshalJSON() ([]byte, error) {
+	type markdown Markdown
+	return marshalWithTag(e.ElementTag(), (*markdown)(e))
+}
+
+// MarshalJSON 编码时加上 tag
+func (e *Hr) MarshalJSON() ([]byte, error) {
+	return marshalWithTag(e.ElementTag(), struct{}{})
+}
+
+// MarshalJSON 编码时加上 tag
+func (e *Img) MarshalJSON() ([]byte, error) {
+	type img Img
+	return marshalWithTag(e.ElementTag(), (*img)(e))
+}
+
+// MarshalJSON 编码时加上 tag
+func (e *Note) MarshalJSON() ([]byte, error) {
+	type note Note
+	return marshalWithTag(e.ElementTag(), (*note)(e))
+}
+
+// MarshalJSON 编码时加上 tag
+func (e *ColumnSet) MarshalJSON() ([]byte, error) {
+	type columnSet ColumnSet
+	return marshalWithTag(e.ElementTag(), (*columnSet)(e))
+}
+
+// MarshalJSON 编码时加上 tag
+func (e *Column) MarshalJSON() ([]byte, error) {
+	type column Column
+	return marshalWithTag(e.ElementTag(), (*column)(e))
+}
+
+// MarshalJSON 编码时加上 tag
+func (e *Action) MarshalJSON() ([]byte, error) {
+	type action Action
+	return marshalWithTag(e.ElementTag(), (*action)(e))
+}
+
+// MarshalJSON 原样输出
+func (e Raw) MarshalJSON() ([]byte, error) {
+	if len(e) == 0 {
+		return []byte("null"), nil
+	}
+	return e, nil
+}
+
+// Validate 校验是否合法的 json 对象
+func (e Raw) Validate() error {
+	if !json.Valid(e) || e.ElementTag() == "" {
+		return fmt.Errorf("Invalid raw element")
+	}
+	return nil
+}
@@ -0,0 +1,104 @@
+package card
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+var (
+	templateVarRe = regexp.MustCompile(`\$\{(\w+)\}`)
+)
+
+// RenderTemplate 在本地使用 variables 填充卡片 json 模板 (例如从卡片搭建工具导出的卡片 json), 模板中的变量以 ${name} 表示:
+//
+//	字符串整个是一个变量时 (如 "${items}"), 替换为变量的 json 值 (可以是数组/对象等)
+//	否则将字符串中的变量替换为变量的文本形式
+//
+// 缺少变量时返回错误
+func RenderTemplate(tmpl []byte, variables map[string]interface{}) (json.RawMessage, error) {
+	dec := json.NewDecoder(bytes.NewReader(tmpl))
+	dec.UseNumber()
+	var v interface{}
+	if err := dec.Decode(&v); err != nil {
+		return nil, err
+	}
+	if _, ok := v.(map[string]interface{}); !ok {
+		return nil, fmt.Errorf("Card template must be a json object")
+	}
+
+	missing := map[string]bool{}
+	v = renderValue(v, variables, missing)
+	if len(missing) != 0 {
+		names := make([]string, 0, len(missing))
+		for name := range missing {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		return nil, fmt.Errorf("Missing card template variables: %s", strings.Join(names, ", "))
+	}
+
+	b, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	return json.RawMessage(b), nil
+}
+
+func renderValue(v interface{}, variables map[string]interface{}, missing map[string]bool) interface{} {
+	switch val := v.(type) {
+	case map[string]interface{}:
+		for k, elem := range val {
+			val[k] = renderValue(elem, variables, missing)
+		}
+		return val
+
+	case []interface{}:
+		for i, elem := range val {
+			val[i] = renderValue(elem, variables, missing)
+		}
+		return val
+
+	case string:
+		// 整个是一个变量
+		if m := templateVarRe.FindStringSubmatch(val); m != nil && m[0] == val {
+			variable, ok := variables[m[1]]
+			if !ok {
+				missing[m[1]] = true
+				return val
+			}
+			return variable
+		}
+		return templateVarRe.ReplaceAllStringFunc(val, func(s string) string {
+			name := s[2 : len(s)-1]
+			variable, ok := variables[name]
+			if !ok {
+				missing[name] = true
+				return s
+			}
+			return variableText(variable)
+		})
+
+	default:
+		return v
+	}
+}
+
+func variableText(variable interface{}) string {
+	switch val := variable.(type) {
+	case string:
+		return val
+	case fmt.Stringer:
+		return val.String()
+	case nil:
+		return ""
+	}
+	b, err := json.Marshal(variable)
+	if err != nil {
+		return fmt.Sprint(variable)
+	}
+	return string(b)
+}
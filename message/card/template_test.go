@@ -0,0 +1,47 @@
+package card
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRenderTemplate(t *testing.T) {
+	assert := assert.New(t)
+
+	tmpl := []byte(`{
+		"header": {"title": {"tag": "plain_text", "content": "${svc} 告警"}, "template": "${color}"},
+		"elements": [
+			{"tag": "markdown", "content": "错误率 **${rate}**, 阈值 ${threshold}"},
+			{"tag": "action", "actions": [{"tag": "button", "text": {"tag": "plain_text", "content": "确认"}, "value": "${value}"}]},
+			{"tag": "img", "img_key": "img_1", "alt": {"tag": "plain_text", "content": "$notvar {x}"}, "preview": true}
+		]
+	}`)
+
+	out, err := RenderTemplate(tmpl, map[string]interface{}{
+		"svc":       "order",
+		"color":     "red",
+		"rate":      "5%",
+		"threshold": 0.01,
+		"value":     map[string]string{"op": "ack"},
+	})
+	assert.NoError(err)
+	assert.JSONEq(`{
+		"header": {"title": {"tag": "plain_text", "content": "order 告警"}, "template": "red"},
+		"elements": [
+			{"tag": "markdown", "content": "错误率 **5%**, 阈值 0.01"},
+			{"tag": "action", "actions": [{"tag": "button", "text": {"tag": "plain_text", "content": "确认"}, "value": {"op": "ack"}}]},
+			{"tag": "img", "img_key": "img_1", "alt": {"tag": "plain_text", "content": "$notvar {x}"}, "preview": true}
+		]
+	}`, string(out))
+
+	_, err = RenderTemplate(tmpl, map[string]interface{}{"svc": "order"})
+	if assert.Error(err) {
+		assert.Equal("Missing card template variables: color, rate, threshold, value", err.Error())
+	}
+
+	_, err = RenderTemplate([]byte(`[]`), nil)
+	assert.Error(err)
+	_, err = RenderTemplate([]byte(`xxx`), nil)
+	assert.Error(err)
+}
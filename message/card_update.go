@@ -0,0 +1,173 @@
+package message
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/huangjunwen/feishu-driver/conf"
+	"github.com/huangjunwen/feishu-driver/utils"
+	"github.com/huangjunwen/feishu-driver/webhook"
+)
+
+var (
+	// CardUpdateTokenTTL 是卡片交互回调中 token 的有效期
+	CardUpdateTokenTTL = 30 * time.Minute
+
+	// CardUpdateTokenMaxUses 是卡片交互回调中 token 的最大使用次数
+	CardUpdateTokenMaxUses = 2
+)
+
+// DelayUpdateCard 使用卡片交互回调中的 token 延迟更新消息卡片, 用于回调响应 (3 秒) 之后更新卡片;
+// token 在 30 分钟内有效，最多使用 2 次
+type DelayUpdateCard struct {
+	// Token 是卡片交互回调中的 token (webhook.CardAction.Token)
+	Token string
+
+	// Content 是新的卡片: *SendCardContent 或 *SendTemplateCardContent
+	Content SendContent
+
+	// OpenIds 非空时仅更新这些用户看到的卡片 (仅适用于非共享卡片), 否则更新所有人看到的卡片;
+	// open_ids 需要位于卡片 json 内, 因此模板卡片不支持
+	OpenIds []string
+}
+
+// CardUpdater 保存卡片交互回调中的 token 及消息 id, 用于在回调返回之后更新该卡片; 可在异步任务中并发使用,
+// 更新会依次进行
+type CardUpdater struct {
+	provider  conf.TenantAccessTokenProvider
+	token     string
+	openId    string
+	messageId string
+	expiresAt time.Time
+
+	mu        sync.Mutex
+	remaining int
+}
+
+// Do 调用 api
+func (req DelayUpdateCard) Do(ctx context.Context, provider conf.TenantAccessTokenProvider) (*utils.APIResultBase, error) {
+	if req.Token == "" {
+		return nil, fmt.Errorf("Missing token")
+	}
+	if err := req.validate(); err != nil {
+		return nil, err
+	}
+
+	var card interface{} = req.Content.(cardContent).sendCard()
+	if len(req.OpenIds) != 0 {
+		// open_ids 位于卡片 json 内
+		b, err := json.Marshal(card)
+		if err != nil {
+			return nil, err
+		}
+		m := map[string]interface{}{}
+		if err := json.Unmarshal(b, &m); err != nil {
+			return nil, err
+		}
+		m["open_ids"] = req.OpenIds
+		card = m
+	}
+
+	body := &struct {
+		Token string      `json:"token"`
+		Card  interface{} `json:"card"`
+	}{
+		Token: req.Token,
+		Card:  card,
+	}
+	result := &utils.APIResultBase{}
+	err := utils.PostJSONWithTenantAccessToken(ctx, "/interactive/v1/card/update", provider, body, result)
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+func (req DelayUpdateCard) validate() error {
+	if _, ok := req.Content.(cardContent); !ok {
+		return fmt.Errorf("DelayUpdateCard only support card content")
+	}
+	if err := validateContent(req.Content); err != nil {
+		return err
+	}
+	if _, ok := req.Content.(*SendTemplateCardContent); ok && len(req.OpenIds) != 0 {
+		return fmt.Errorf("DelayUpdateCard does not support open ids for template card content")
+	}
+	return nil
+}
+
+// NewCardUpdater 在卡片交互回调中创建 CardUpdater, 有效期从此时开始计算
+func NewCardUpdater(provider conf.TenantAccessTokenProvider, action *webhook.CardAction) *CardUpdater {
+	return &CardUpdater{
+		provider:  provider,
+		token:     action.Token,
+		openId:    action.OpenId,
+		messageId: action.OpenMessageId,
+		expiresAt: time.Now().Add(CardUpdateTokenTTL),
+		remaining: CardUpdateTokenMaxUses,
+	}
+}
+
+// Update 更新所有人看到的卡片: token 可用时使用 DelayUpdateCard, 否则 (过期或次数用尽) 使用 PatchMessage
+// (仅适用于共享卡片)
+func (u *CardUpdater) Update(ctx context.Context, content SendContent) error {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+
+	if u.tokenUsable() {
+		return u.delayUpdate(ctx, content, nil)
+	}
+	if u.messageId == "" {
+		return fmt.Errorf("Card update token unusable and missing message id")
+	}
+	res, err := PatchMessage{
+		MessageId: u.messageId,
+		Content:   content,
+	}.Do(ctx, u.provider)
+	if err != nil {
+		return err
+	}
+	return res.ResultError()
+}
+
+// UpdateForClicker 仅更新交互用户看到的卡片 (仅适用于非共享卡片, 不支持模板卡片), 只能使用 token 更新
+func (u *CardUpdater) UpdateForClicker(ctx context.Context, content SendContent) error {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+
+	if !u.tokenUsable() {
+		return fmt.Errorf("Card update token expired or used up")
+	}
+	if u.openId == "" {
+		return fmt.Errorf("Missing clicker open id")
+	}
+	return u.delayUpdate(ctx, content, []string{u.openId})
+}
+
+// tokenUsable 判断 token 是否仍可用, 需要持有 u.mu
+func (u *CardUpdater) tokenUsable() bool {
+	return u.token != "" && u.remaining > 0 && time.Now().Before(u.expiresAt)
+}
+
+// delayUpdate 使用 token 更新卡片, 需要持有 u.mu; 请求发出后即计入使用次数
+func (u *CardUpdater) delayUpdate(ctx context.Context, content SendContent, openIds []string) error {
+	req := DelayUpdateCard{
+		Token:   u.token,
+		Content: content,
+		OpenIds: openIds,
+	}
+	// 先校验，避免无效内容消耗使用次数
+	if err := req.validate(); err != nil {
+		return err
+	}
+
+	u.remaining--
+	res, err := req.Do(ctx, u.provider)
+	if err != nil {
+		return err
+	}
+	return res.ResultError()
+}
@@ -0,0 +1,106 @@
+package message
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/huangjunwen/feishu-driver/conf"
+	"github.com/huangjunwen/feishu-driver/message/card"
+	"github.com/huangjunwen/feishu-driver/utils"
+	"github.com/huangjunwen/feishu-driver/webhook"
+)
+
+func TestCardUpdate(t *testing.T) {
+	assert := assert.New(t)
+
+	srv, reqs := newIMTestServer(func(r *http.Request) string {
+		return `{"code": 0, "msg": "success"}`
+	})
+	defer srv.Close()
+	ctx := utils.APIOptions{URLBase: srv.URL}.WithCtx(context.Background())
+	provider := conf.TenantAccessTokenProviderFunc(func() (string, error) { return "t-token", nil })
+	methods := func() []string {
+		ret := []string{}
+		for _, r := range *reqs {
+			ret = append(ret, r.Method+" "+r.URI)
+		}
+		return ret
+	}
+	sentCard := func(i int) map[string]interface{} {
+		body := map[string]interface{}{}
+		assert.NoError(json.Unmarshal([]byte((*reqs)[i].Body), &body))
+		return body["card"].(map[string]interface{})
+	}
+
+	content := &SendCardContent{Card: card.New().Add(card.NewMarkdown("已审批"))}
+
+	// 直接调用
+	{
+		_, err := DelayUpdateCard{Content: content}.Do(ctx, provider)
+		assert.Error(err)
+		_, err = DelayUpdateCard{Token: "c-1", Content: &SendTextContent{Text: "x"}}.Do(ctx, provider)
+		assert.Error(err)
+
+		res, err := DelayUpdateCard{Token: "c-1", Content: content, OpenIds: []string{"ou_1"}}.Do(ctx, provider)
+		assert.NoError(err)
+		assert.NoError(res.ResultError())
+		assert.Equal([]string{"POST /interactive/v1/card/update"}, methods())
+		assert.Contains((*reqs)[0].Body, `"token":"c-1"`)
+		assert.Equal([]interface{}{"ou_1"}, sentCard(0)["open_ids"])
+		*reqs = nil
+
+		// 模板卡片: 更新所有人看到的卡片, 不支持 open_ids
+		template := &SendTemplateCardContent{TemplateId: "ctp_1"}
+		_, err = DelayUpdateCard{Token: "c-1", Content: template, OpenIds: []string{"ou_1"}}.Do(ctx, provider)
+		assert.Error(err)
+		assert.Len(*reqs, 0)
+		_, err = DelayUpdateCard{Token: "c-1", Content: template}.Do(ctx, provider)
+		assert.NoError(err)
+		assert.Equal(map[string]interface{}{
+			"type": "template",
+			"data": map[string]interface{}{"template_id": "ctp_1"},
+		}, sentCard(0))
+		*reqs = nil
+	}
+
+	action := &webhook.CardAction{OpenId: "ou_1", OpenMessageId: "om_1", Token: "c-2"}
+
+	// 仅更新交互用户看到的卡片
+	{
+		u := NewCardUpdater(provider, action)
+		// 模板卡片不消耗使用次数
+		assert.Error(u.UpdateForClicker(ctx, &SendTemplateCardContent{TemplateId: "ctp_1"}))
+		assert.NoError(u.UpdateForClicker(ctx, content))
+		assert.Equal([]interface{}{"ou_1"}, sentCard(0)["open_ids"])
+		assert.NoError(u.UpdateForClicker(ctx, content))
+		// 次数用尽
+		assert.Error(u.UpdateForClicker(ctx, content))
+		assert.Len(*reqs, 2)
+		*reqs = nil
+	}
+
+	// 并发更新: token 用尽后使用 PatchMessage
+	{
+		u := NewCardUpdater(provider, action)
+		wg := &sync.WaitGroup{}
+		for i := 0; i < 4; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				assert.NoError(u.Update(ctx, content))
+			}()
+		}
+		wg.Wait()
+		assert.ElementsMatch([]string{
+			"POST /interactive/v1/card/update",
+			"POST /interactive/v1/card/update",
+			"PATCH /im/v1/messages/om_1",
+			"PATCH /im/v1/messages/om_1",
+		}, methods())
+	}
+}
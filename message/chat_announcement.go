@@ -0,0 +1,139 @@
+package message
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+
+	"github.com/huangjunwen/feishu-driver/conf"
+	"github.com/huangjunwen/feishu-driver/utils"
+)
+
+// ChatAnnouncement 是群公告, 内容为旧版文档格式
+type ChatAnnouncement struct {
+	// Content 是公告内容 (旧版文档 json), 可使用 Document 解析
+	Content string `json:"content"`
+
+	// Revision 是公告当前版本号, 修改公告时需要提供
+	Revision string `json:"revision"`
+
+	// CreateTime/UpdateTime 是秒级时间戳
+	CreateTime string `json:"create_time"`
+	UpdateTime string `json:"update_time"`
+
+	OwnerIdType    string `json:"owner_id_type"`
+	OwnerId        string `json:"owner_id"`
+	ModifierIdType string `json:"modifier_id_type"`
+	ModifierId     string `json:"modifier_id"`
+}
+
+// ChatAnnouncementResult 是获取群公告接口的结果
+type ChatAnnouncementResult struct {
+	utils.APIResultBase
+
+	Data *ChatAnnouncement `json:"data"`
+}
+
+// PatchChatAnnouncement 更新群公告 (im/v1)
+type PatchChatAnnouncement struct {
+	// ChatId 是会话 id
+	ChatId string
+
+	// Revision 是修改基于的公告版本号, 一般为 GetChatAnnouncement 返回的 Revision;
+	// 公告在此期间被修改时接口会返回错误
+	Revision string
+
+	// Requests 是修改公告的请求, 可使用 NewInsertBlocksRequest/NewUpdateTitleRequest/NewDeleteContentRangeRequest 创建
+	Requests []*AnnouncementRequest
+}
+
+// GetChatAnnouncement 获取群公告 (im/v1)
+func GetChatAnnouncement(ctx context.Context, provider conf.TenantAccessTokenProvider, chatId string) (*ChatAnnouncementResult, error) {
+	params := url.Values{}
+	params.Set("user_id_type", ReceiveIdTypeOpenId)
+	result := &ChatAnnouncementResult{}
+	err := utils.GetJSONWithTenantAccessToken(ctx, chatAnnouncementPath(chatId), provider, params, result)
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// Document 解析公告内容
+func (a *ChatAnnouncement) Document() (*AnnouncementDocument, error) {
+	doc := &AnnouncementDocument{}
+	if err := json.Unmarshal([]byte(a.Content), doc); err != nil {
+		return nil, err
+	}
+	return doc, nil
+}
+
+// Do 调用 api
+func (req PatchChatAnnouncement) Do(ctx context.Context, provider conf.TenantAccessTokenProvider) (*utils.APIResultBase, error) {
+	if req.Revision == "" {
+		return nil, fmt.Errorf("Missing revision")
+	}
+	if len(req.Requests) == 0 {
+		return nil, fmt.Errorf("Missing requests")
+	}
+	// 每个请求编码为一个 json 字符串
+	requests := make([]string, 0, len(req.Requests))
+	for _, r := range req.Requests {
+		b, err := json.Marshal(r)
+		if err != nil {
+			return nil, err
+		}
+		requests = append(requests, string(b))
+	}
+	body := &struct {
+		Revision string   `json:"revision"`
+		Requests []string `json:"requests"`
+	}{
+		Revision: req.Revision,
+		Requests: requests,
+	}
+	result := &utils.APIResultBase{}
+	err := utils.PatchJSONWithTenantAccessToken(ctx, chatAnnouncementPath(req.ChatId), provider, body, result)
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// EditChatAnnouncement 获取群公告最新版本, 交给 fn 生成修改请求后基于该版本更新群公告;
+// fn 返回空的请求时不更新
+func EditChatAnnouncement(ctx context.Context, provider conf.TenantAccessTokenProvider, chatId string, fn func(announcement *ChatAnnouncement) ([]*AnnouncementRequest, error)) error {
+	res, err := GetChatAnnouncement(ctx, provider, chatId)
+	if err != nil {
+		return err
+	}
+	if err := res.ResultError(); err != nil {
+		return err
+	}
+	if res.Data == nil {
+		return fmt.Errorf("Missing announcement data")
+	}
+
+	requests, err := fn(res.Data)
+	if err != nil {
+		return err
+	}
+	if len(requests) == 0 {
+		return nil
+	}
+
+	patchRes, err := PatchChatAnnouncement{
+		ChatId:   chatId,
+		Revision: res.Data.Revision,
+		Requests: requests,
+	}.Do(ctx, provider)
+	if err != nil {
+		return err
+	}
+	return patchRes.ResultError()
+}
+
+func chatAnnouncementPath(chatId string) string {
+	return "/im/v1/chats/" + url.PathEscape(chatId) + "/announcement"
+}
@@ -0,0 +1,307 @@
+package message
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+var (
+	// AnnouncementBlockTypeParagraph 等是公告文档中的块类型
+	AnnouncementBlockTypeParagraph      = "paragraph"
+	AnnouncementBlockTypeHorizontalLine = "horizontalLine"
+
+	// AnnouncementElementTypeTextRun 等是段落中的行内元素类型
+	AnnouncementElementTypeTextRun = "textRun"
+	AnnouncementElementTypePerson  = "person"
+)
+
+// AnnouncementDocument 是群公告的内容 (旧版文档格式), 可由 ChatAnnouncement.Document 解析得到
+type AnnouncementDocument struct {
+	Title *AnnouncementParagraph `json:"title,omitempty"`
+	Body  struct {
+		Blocks []*AnnouncementBlock `json:"blocks"`
+	} `json:"body"`
+}
+
+// AnnouncementBlock 是公告文档中的块, Type 决定了哪个字段有值; 不支持的块类型保留在 Raw 中
+type AnnouncementBlock struct {
+	Type           string                 `json:"type"`
+	Paragraph      *AnnouncementParagraph `json:"paragraph,omitempty"`
+	HorizontalLine *struct{}              `json:"horizontalLine,omitempty"`
+
+	// Raw 是不支持的块的原始 json, 编码时原样输出
+	Raw json.RawMessage `json:"-"`
+}
+
+// AnnouncementParagraph 是公告文档中的段落
+type AnnouncementParagraph struct {
+	Elements []*AnnouncementElement `json:"elements"`
+}
+
+// AnnouncementElement 是段落中的行内元素, Type 决定了哪个字段有值
+type AnnouncementElement struct {
+	Type    string               `json:"type"`
+	TextRun *AnnouncementTextRun `json:"textRun,omitempty"`
+	Person  *AnnouncementPerson  `json:"person,omitempty"`
+}
+
+// AnnouncementTextRun 是一段文本
+type AnnouncementTextRun struct {
+	Text  string                 `json:"text"`
+	Style *AnnouncementTextStyle `json:"style,omitempty"`
+}
+
+// AnnouncementTextStyle 是文本样式
+type AnnouncementTextStyle struct {
+	Bold bool `json:"bold,omitempty"`
+	Link *struct {
+		URL string `json:"url"`
+	} `json:"link,omitempty"`
+}
+
+// AnnouncementPerson 是 @ 的用户
+type AnnouncementPerson struct {
+	OpenId string `json:"openId"`
+}
+
+// AnnouncementRequest 是修改群公告的请求 (旧版文档的 requests 格式), 可使用 NewInsertBlocksRequest 等函数创建
+type AnnouncementRequest struct {
+	RequestType string `json:"requestType"`
+
+	InsertBlocksRequest *struct {
+		// Payload 是 {"blocks": [...]} 的 json
+		Payload  string                `json:"payload"`
+		Location *AnnouncementLocation `json:"location"`
+	} `json:"insertBlocksRequest,omitempty"`
+
+	UpdateTitleRequest *struct {
+		// Payload 是标题段落的 json
+		Payload string `json:"payload"`
+	} `json:"updateTitleRequest,omitempty"`
+
+	DeleteContentRangeRequest *struct {
+		DeleteRange *AnnouncementRange `json:"deleteRange"`
+	} `json:"deleteContentRangeRequest,omitempty"`
+}
+
+// AnnouncementLocation 是插入的位置
+type AnnouncementLocation struct {
+	ZoneId    string `json:"zoneId"`
+	Index     int    `json:"index"`
+	EndOfZone bool   `json:"endOfZone,omitempty"`
+}
+
+// AnnouncementRange 是删除的范围 [StartIndex, EndIndex)
+type AnnouncementRange struct {
+	ZoneId     string `json:"zoneId"`
+	StartIndex int    `json:"startIndex"`
+	EndIndex   int    `json:"endIndex"`
+}
+
+// NewAnnouncementBlocks 将文本 (*SendTextContent, 每行一个段落) 或富文本 (*SendPostContent, 优先使用 zh_cn)
+// 转换为公告文档的块; 富文本中的图片不支持
+func NewAnnouncementBlocks(content SendContent) ([]*AnnouncementBlock, error) {
+	blocks := []*AnnouncementBlock{}
+	switch c := content.(type) {
+	case *SendTextContent:
+		for _, line := range strings.Split(c.Text, "\n") {
+			blocks = append(blocks, newAnnouncementParagraphBlock(newAnnouncementText(line, "")))
+		}
+
+	case *SendPostContent:
+		lc := c.localeContent()
+		if lc == nil {
+			return nil, fmt.Errorf("Empty post content")
+		}
+		for i, paragraph := range lc.Content {
+			elems := make([]*AnnouncementElement, 0, len(paragraph))
+			for _, elem := range paragraph {
+				switch elem.Tag {
+				case "text":
+					elems = append(elems, newAnnouncementText(elem.Text, ""))
+				case "a":
+					elems = append(elems, newAnnouncementText(elem.Text, elem.Href))
+				case "at":
+					elems = append(elems, &AnnouncementElement{
+						Type:   AnnouncementElementTypePerson,
+						Person: &AnnouncementPerson{OpenId: elem.UserId},
+					})
+				default:
+					return nil, fmt.Errorf("Paragraph %d: unsupported post element %q in announcement", i, elem.Tag)
+				}
+			}
+			blocks = append(blocks, newAnnouncementParagraphBlock(elems...))
+		}
+
+	default:
+		return nil, fmt.Errorf("Unsupported announcement content %T", content)
+	}
+	return blocks, nil
+}
+
+// NewInsertBlocksRequest 创建在正文 index 处插入块的请求, index 小于 0 时插入到正文末尾
+func NewInsertBlocksRequest(index int, blocks ...*AnnouncementBlock) (*AnnouncementRequest, error) {
+	payload, err := json.Marshal(map[string]interface{}{"blocks": blocks})
+	if err != nil {
+		return nil, err
+	}
+	req := &AnnouncementRequest{RequestType: "InsertBlocksRequestType"}
+	req.InsertBlocksRequest = &struct {
+		Payload  string                `json:"payload"`
+		Location *AnnouncementLocation `json:"location"`
+	}{
+		Payload:  string(payload),
+		Location: &AnnouncementLocation{ZoneId: "0", Index: index},
+	}
+	if index < 0 {
+		req.InsertBlocksRequest.Location.Index = 0
+		req.InsertBlocksRequest.Location.EndOfZone = true
+	}
+	return req, nil
+}
+
+// NewUpdateTitleRequest 创建修改标题的请求
+func NewUpdateTitleRequest(title string) (*AnnouncementRequest, error) {
+	payload, err := json.Marshal(&AnnouncementParagraph{
+		Elements: []*AnnouncementElement{newAnnouncementText(title, "")},
+	})
+	if err != nil {
+		return nil, err
+	}
+	req := &AnnouncementRequest{RequestType: "UpdateTitleRequestType"}
+	req.UpdateTitleRequest = &struct {
+		Payload string `json:"payload"`
+	}{
+		Payload: string(payload),
+	}
+	return req, nil
+}
+
+// NewDeleteContentRangeRequest 创建删除正文 [startIndex, endIndex) 范围内容的请求
+func NewDeleteContentRangeRequest(startIndex, endIndex int) *AnnouncementRequest {
+	req := &AnnouncementRequest{RequestType: "DeleteContentRangeRequestType"}
+	req.DeleteContentRangeRequest = &struct {
+		DeleteRange *AnnouncementRange `json:"deleteRange"`
+	}{
+		DeleteRange: &AnnouncementRange{ZoneId: "0", StartIndex: startIndex, EndIndex: endIndex},
+	}
+	return req
+}
+
+// TitleText 返回标题文本
+func (doc *AnnouncementDocument) TitleText() string {
+	if doc.Title == nil {
+		return ""
+	}
+	return doc.Title.PlainText()
+}
+
+// ToPost 将公告文档转换为 zh_cn 的富文本 (标题/段落中的文本/链接/@), 不支持的块被忽略
+func (doc *AnnouncementDocument) ToPost() *SendPostContent {
+	lc := &PostLocaleContent{
+		Title:   doc.TitleText(),
+		Content: [][]*PostElement{},
+	}
+	for _, block := range doc.Body.Blocks {
+		if block.Paragraph == nil {
+			continue
+		}
+		paragraph := []*PostElement{}
+		for _, elem := range block.Paragraph.Elements {
+			switch {
+			case elem.TextRun != nil && elem.TextRun.Style != nil && elem.TextRun.Style.Link != nil:
+				paragraph = append(paragraph, &PostElement{Tag: "a", Text: elem.TextRun.Text, Href: elem.TextRun.Style.Link.URL})
+			case elem.TextRun != nil:
+				if elem.TextRun.Text != "" {
+					paragraph = append(paragraph, &PostElement{Tag: "text", Text: elem.TextRun.Text})
+				}
+			case elem.Person != nil:
+				paragraph = append(paragraph, &PostElement{Tag: "at", UserId: elem.Person.OpenId})
+			}
+		}
+		if len(paragraph) != 0 {
+			lc.Content = append(lc.Content, paragraph)
+		}
+	}
+	return &SendPostContent{Post: map[string]*PostLocaleContent{PostLocales[0]: lc}}
+}
+
+// PlainText 返回段落的纯文本, @ 输出为 @open_id
+func (p *AnnouncementParagraph) PlainText() string {
+	b := strings.Builder{}
+	for _, elem := range p.Elements {
+		switch {
+		case elem.TextRun != nil:
+			b.WriteString(elem.TextRun.Text)
+		case elem.Person != nil:
+			b.WriteString("@" + elem.Person.OpenId)
+		}
+	}
+	return b.String()
+}
+
+// UnmarshalJSON 解析块, 不支持的块类型保留原始 json
+func (block *AnnouncementBlock) UnmarshalJSON(data []byte) error {
+	type plain AnnouncementBlock
+	p := &plain{}
+	if err := json.Unmarshal(data, p); err != nil {
+		return err
+	}
+	*block = AnnouncementBlock(*p)
+	switch block.Type {
+	case AnnouncementBlockTypeParagraph, AnnouncementBlockTypeHorizontalLine:
+	default:
+		block.Raw = append(json.RawMessage(nil), data...)
+	}
+	return nil
+}
+
+// MarshalJSON 编码块, 有 Raw 时原样输出
+func (block *AnnouncementBlock) MarshalJSON() ([]byte, error) {
+	if block.Raw != nil {
+		return block.Raw, nil
+	}
+	type plain AnnouncementBlock
+	return json.Marshal((*plain)(block))
+}
+
+func newAnnouncementParagraphBlock(elems ...*AnnouncementElement) *AnnouncementBlock {
+	return &AnnouncementBlock{
+		Type:      AnnouncementBlockTypeParagraph,
+		Paragraph: &AnnouncementParagraph{Elements: elems},
+	}
+}
+
+func newAnnouncementText(text, href string) *AnnouncementElement {
+	elem := &AnnouncementElement{
+		Type:    AnnouncementElementTypeTextRun,
+		TextRun: &AnnouncementTextRun{Text: text},
+	}
+	if href != "" {
+		elem.TextRun.Style = &AnnouncementTextStyle{}
+		elem.TextRun.Style.Link = &struct {
+			URL string `json:"url"`
+		}{URL: href}
+	}
+	return elem
+}
+
+// localeContent 返回优先使用的语言的内容: 按 PostLocales 的顺序, 其次任一语言
+func (c *SendPostContent) localeContent() *PostLocaleContent {
+	for _, locale := range PostLocales {
+		if lc := c.Post[locale]; lc != nil {
+			return lc
+		}
+	}
+	locales := make([]string, 0, len(c.Post))
+	for locale := range c.Post {
+		locales = append(locales, locale)
+	}
+	sort.Strings(locales)
+	if len(locales) == 0 {
+		return nil
+	}
+	return c.Post[locales[0]]
+}
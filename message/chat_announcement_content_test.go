@@ -0,0 +1,73 @@
+package message
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAnnouncementContent(t *testing.T) {
+	assert := assert.New(t)
+
+	post, err := NewPostBuilder().
+		Title("发布通知").
+		Text("服务 svc 已发布到 ").Link("prod", "https://example.com").
+		Paragraph().At("ou_1").Text(" 请关注").
+		Build()
+	assert.NoError(err)
+
+	// 富文本 -> 块 -> 插入请求
+	blocks, err := NewAnnouncementBlocks(post)
+	assert.NoError(err)
+	insert, err := NewInsertBlocksRequest(-1, blocks...)
+	assert.NoError(err)
+	title, err := NewUpdateTitleRequest("发布通知")
+	assert.NoError(err)
+
+	b, err := json.Marshal(insert)
+	assert.NoError(err)
+	req := &AnnouncementRequest{}
+	assert.NoError(json.Unmarshal(b, req))
+	assert.Equal("InsertBlocksRequestType", req.RequestType)
+	assert.Equal(&AnnouncementLocation{ZoneId: "0", Index: 0, EndOfZone: true}, req.InsertBlocksRequest.Location)
+	assert.JSONEq(`{"blocks": [
+		{"type": "paragraph", "paragraph": {"elements": [
+			{"type": "textRun", "textRun": {"text": "服务 svc 已发布到 "}},
+			{"type": "textRun", "textRun": {"text": "prod", "style": {"link": {"url": "https://example.com"}}}}
+		]}},
+		{"type": "paragraph", "paragraph": {"elements": [
+			{"type": "person", "person": {"openId": "ou_1"}},
+			{"type": "textRun", "textRun": {"text": " 请关注"}}
+		]}}
+	]}`, req.InsertBlocksRequest.Payload)
+
+	// 由请求的 payload 组成文档 -> 富文本
+	content := `{"title": ` + title.UpdateTitleRequest.Payload + `, "body": ` + req.InsertBlocksRequest.Payload + `}`
+	doc, err := (&ChatAnnouncement{Content: content}).Document()
+	assert.NoError(err)
+	assert.Equal("发布通知", doc.TitleText())
+	assert.Equal(post, doc.ToPost())
+	assert.Equal("@ou_1 请关注", doc.Body.Blocks[1].Paragraph.PlainText())
+
+	// 不支持的块原样保留
+	doc, err = (&ChatAnnouncement{Content: `{"body": {"blocks": [{"type": "table", "table": {"rows": 1}}, {"type": "horizontalLine", "horizontalLine": {}}]}}`}).Document()
+	assert.NoError(err)
+	b, err = json.Marshal(doc.Body.Blocks)
+	assert.NoError(err)
+	assert.JSONEq(`[{"type": "table", "table": {"rows": 1}}, {"type": "horizontalLine", "horizontalLine": {}}]`, string(b))
+	assert.Len(doc.ToPost().Post["zh_cn"].Content, 0)
+
+	// 文本每行一个段落
+	blocks, err = NewAnnouncementBlocks(&SendTextContent{Text: "a\nb"})
+	assert.NoError(err)
+	assert.Len(blocks, 2)
+
+	// 不支持的内容
+	_, err = NewAnnouncementBlocks(&SendPostContent{Post: map[string]*PostLocaleContent{"en_us": {Content: [][]*PostElement{{{Tag: "img", ImageKey: "k"}}}}}})
+	assert.Error(err)
+	_, err = NewAnnouncementBlocks(&SendPostContent{})
+	assert.Error(err)
+	_, err = (&ChatAnnouncement{Content: "x"}).Document()
+	assert.Error(err)
+}
@@ -0,0 +1,68 @@
+package message
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/huangjunwen/feishu-driver/conf"
+	"github.com/huangjunwen/feishu-driver/utils"
+)
+
+// SendEphemeral 发送仅指定用户可见的临时消息卡片, 只能在群聊中发送, 且只支持卡片
+type SendEphemeral struct {
+	// ChatId 是群聊 id
+	ChatId string `json:"chat_id"`
+
+	// 可见的用户，只需要填 open_id、user_id、email 中的一个即可
+	OpenId string `json:"open_id,omitempty"`
+	UserId string `json:"user_id,omitempty"`
+	Email  string `json:"email,omitempty"`
+
+	// Content 是卡片内容: *SendCardContent 或 *SendTemplateCardContent
+	Content SendContent `json:"-"`
+
+	// MsgType 是内容类型，不需要填写
+	MsgType string `json:"msg_type"`
+
+	// Card 是卡片内容，不需要填写，由 Content 获得
+	Card interface{} `json:"card"`
+}
+
+// Do 调用 api
+func (send SendEphemeral) Do(ctx context.Context, provider conf.TenantAccessTokenProvider) (*SendResult, error) {
+	if send.ChatId == "" {
+		return nil, fmt.Errorf("Missing chat id")
+	}
+	if send.OpenId == "" && send.UserId == "" && send.Email == "" {
+		return nil, fmt.Errorf("Missing user")
+	}
+	c, ok := send.Content.(cardContent)
+	if !ok {
+		return nil, fmt.Errorf("SendEphemeral only support card content")
+	}
+	if err := validateContent(send.Content); err != nil {
+		return nil, err
+	}
+	send.MsgType = send.Content.SendContentMsgType()
+	send.Card = c.sendCard()
+
+	result := &SendResult{}
+	err := utils.PostJSONWithTenantAccessToken(ctx, "/ephemeral/v1/send", provider, send, result)
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// DeleteEphemeral 删除临时消息卡片, messageId 是 SendEphemeral 返回的 MessageId
+func DeleteEphemeral(ctx context.Context, provider conf.TenantAccessTokenProvider, messageId string) (*utils.APIResultBase, error) {
+	body := map[string]string{
+		"message_id": messageId,
+	}
+	result := &utils.APIResultBase{}
+	err := utils.PostJSONWithTenantAccessToken(ctx, "/ephemeral/v1/delete", provider, body, result)
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
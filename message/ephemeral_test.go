@@ -0,0 +1,58 @@
+package message
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/huangjunwen/feishu-driver/conf"
+	"github.com/huangjunwen/feishu-driver/message/card"
+	"github.com/huangjunwen/feishu-driver/utils"
+)
+
+func TestEphemeral(t *testing.T) {
+	assert := assert.New(t)
+
+	srv, reqs := newIMTestServer(func(r *http.Request) string {
+		return `{"code": 0, "data": {"message_id": "om_e1"}}`
+	})
+	defer srv.Close()
+	ctx := utils.APIOptions{URLBase: srv.URL}.WithCtx(context.Background())
+	provider := conf.TenantAccessTokenProviderFunc(func() (string, error) { return "t-token", nil })
+	last := func() recordedRequest { return (*reqs)[len(*reqs)-1] }
+
+	content := &SendCardContent{Card: card.New().Add(card.NewMarkdown("only you"))}
+
+	// 错误
+	for _, send := range []SendEphemeral{
+		{OpenId: "ou_1", Content: content},
+		{ChatId: "oc_1", Content: content},
+		{ChatId: "oc_1", OpenId: "ou_1", Content: &SendTextContent{Text: "hi"}},
+		{ChatId: "oc_1", OpenId: "ou_1", Content: &SendCardContent{}},
+	} {
+		_, err := send.Do(ctx, provider)
+		assert.Error(err)
+	}
+	assert.Len(*reqs, 0)
+
+	res, err := SendEphemeral{ChatId: "oc_1", OpenId: "ou_1", Content: content}.Do(ctx, provider)
+	assert.NoError(err)
+	assert.Equal("om_e1", res.Data.MessageId)
+	assert.Equal("/ephemeral/v1/send", last().URI)
+	assert.JSONEq(`{
+		"chat_id": "oc_1",
+		"open_id": "ou_1",
+		"msg_type": "interactive",
+		"card": {
+			"config": {"wide_screen_mode": true, "enable_forward": true},
+			"elements": [{"tag": "markdown", "content": "only you"}]
+		}
+	}`, last().Body)
+
+	_, err = DeleteEphemeral(ctx, provider, "om_e1")
+	assert.NoError(err)
+	assert.Equal("/ephemeral/v1/delete", last().URI)
+	assert.JSONEq(`{"message_id": "om_e1"}`, last().Body)
+}
@@ -0,0 +1,273 @@
+package message
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/huangjunwen/golibs/logr"
+
+	"github.com/huangjunwen/feishu-driver/conf"
+	"github.com/huangjunwen/feishu-driver/webhook"
+	"github.com/huangjunwen/feishu-driver/webhook/events"
+)
+
+var (
+	// DefaultEscalationSteps 是默认的升级加急步骤: 立即应用内加急, 5 分钟后短信加急, 15 分钟后电话加急
+	DefaultEscalationSteps = []EscalationStep{
+		{Type: UrgentTypeApp, After: 0},
+		{Type: UrgentTypeSMS, After: 5 * time.Minute},
+		{Type: UrgentTypePhone, After: 15 * time.Minute},
+	}
+)
+
+// EscalationStep 是升级加急的一步
+type EscalationStep struct {
+	// Type 是加急类型
+	Type string
+
+	// After 是距离消息发送的时间
+	After time.Duration
+}
+
+// Escalator 用于升级加急: 发送消息 (通常是卡片) 后按步骤依次对用户加急, 直到消息被确认.
+//
+// 以下情况视为确认: 加急的用户已读消息 (需要将消息已读事件交给 HandleEvent), 消息卡片有交互 (需要使用 WrapCardActionHandler),
+// 或者调用 Ack
+type Escalator struct {
+	provider conf.TenantAccessTokenProvider
+	steps    []EscalationStep
+	onStep   func(es *Escalation, step EscalationStep, result *UrgentResult, err error)
+	logger   logr.Logger
+
+	mu      sync.Mutex
+	pending map[string]*Escalation // message_id -> Escalation
+}
+
+// Escalation 是一次升级加急
+type Escalation struct {
+	// MessageId 是发送的消息
+	MessageId string
+
+	// OpenIds 是加急的用户
+	OpenIds []string
+
+	stop chan struct{} // 确认/结束时关闭
+	once sync.Once
+	done chan struct{} // 加急 goroutine 退出时关闭
+
+	cancel context.CancelFunc // 取消进行中的加急请求
+
+	mu      sync.Mutex
+	acked   bool
+	ackedBy string
+}
+
+// detachedContext 沿用 parent 中的值, 但不会被取消
+type detachedContext struct {
+	parent context.Context
+}
+
+// NewEscalator 创建 Escalator
+func NewEscalator(provider conf.TenantAccessTokenProvider, opts ...EscalatorOption) (*Escalator, error) {
+	e := &Escalator{
+		provider: provider,
+		steps:    DefaultEscalationSteps,
+		onStep:   func(*Escalation, EscalationStep, *UrgentResult, error) {},
+		logger:   logr.Nop,
+		pending:  map[string]*Escalation{},
+	}
+	for _, opt := range opts {
+		if err := opt(e); err != nil {
+			return nil, err
+		}
+	}
+	return e, nil
+}
+
+// Start 发送消息并开始升级加急 openIds 中的用户 (必须是消息所在会话的成员), 所有步骤执行完毕/确认/停止时结束.
+//
+// ctx 仅用于发送消息: 之后的加急在后台进行，不受 ctx 取消/超时的影响 (因此可以直接使用 http 请求的 ctx),
+// 但会沿用 ctx 中的值 (如 utils.APIOptions); 需要提前结束时调用 Escalation.Stop 或 Escalator.Stop
+func (e *Escalator) Start(ctx context.Context, req CreateMessage, openIds []string) (*Escalation, error) {
+	if len(openIds) == 0 {
+		return nil, fmt.Errorf("Missing open ids")
+	}
+	res, err := req.Do(ctx, e.provider)
+	if err != nil {
+		return nil, err
+	}
+	if err := res.ResultError(); err != nil {
+		return nil, err
+	}
+
+	es := &Escalation{
+		MessageId: res.Data.MessageId,
+		OpenIds:   openIds,
+		stop:      make(chan struct{}),
+		done:      make(chan struct{}),
+	}
+	runCtx, cancel := context.WithCancel(detachedContext{ctx})
+	es.cancel = cancel
+	e.mu.Lock()
+	e.pending[es.MessageId] = es
+	e.mu.Unlock()
+
+	go e.run(runCtx, es, time.Now())
+	return es, nil
+}
+
+func (e *Escalator) run(ctx context.Context, es *Escalation, start time.Time) {
+	defer func() {
+		e.mu.Lock()
+		delete(e.pending, es.MessageId)
+		e.mu.Unlock()
+		es.finish(false, "")
+		close(es.done)
+	}()
+
+	for _, step := range e.steps {
+		timer := time.NewTimer(step.After - time.Since(start))
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return
+		case <-es.stop:
+			timer.Stop()
+			return
+		case <-timer.C:
+		}
+		// 计时器与确认/停止同时就绪时 select 会随机选择, 加急前需要再检查一次
+		select {
+		case <-ctx.Done():
+			return
+		case <-es.stop:
+			return
+		default:
+		}
+
+		res, err := Urgent{
+			MessageId: es.MessageId,
+			Type:      step.Type,
+			UserIds:   es.OpenIds,
+		}.Do(ctx, e.provider)
+		if err == nil {
+			err = res.ResultError()
+		}
+		if err != nil {
+			e.logger.Error(err, "Urgent error", "messageId", es.MessageId, "type", step.Type)
+		}
+		e.onStep(es, step, res, err)
+	}
+}
+
+// Ack 确认消息, by 是确认者; 返回 false 表示消息不在加急中
+func (e *Escalator) Ack(messageId, by string) bool {
+	e.mu.Lock()
+	es := e.pending[messageId]
+	e.mu.Unlock()
+	if es == nil {
+		return false
+	}
+	return es.finish(true, by)
+}
+
+// HandleEvent 处理消息已读事件 (*events.MessageRead 或 *events.MessageReadV1), 加急的用户已读时确认消息;
+// 其它事件直接忽略并返回 handled=false
+func (e *Escalator) HandleEvent(ctx context.Context, ev interface{}) (handled bool, err error) {
+	var (
+		reader     string
+		messageIds []string
+	)
+	switch e := ev.(type) {
+	case *events.MessageRead:
+		reader, messageIds = e.OpenId, e.OpenMessageIds
+	case *events.MessageReadV1:
+		reader, messageIds = e.Reader.ReaderId.OpenId, e.MessageIdList
+	default:
+		return false, nil
+	}
+
+	for _, messageId := range messageIds {
+		e.mu.Lock()
+		es := e.pending[messageId]
+		e.mu.Unlock()
+		if es != nil && containsString(es.OpenIds, reader) {
+			es.finish(true, reader)
+		}
+	}
+	return true, nil
+}
+
+// WrapCardActionHandler 返回一个 webhook.CardActionHandler: 加急中的消息卡片有任意交互时确认消息, 然后交给 next 处理
+// (next 为 nil 时返回空响应)
+func (e *Escalator) WrapCardActionHandler(next webhook.CardActionHandler) webhook.CardActionHandler {
+	return func(r *http.Request, action *webhook.CardAction) (interface{}, error) {
+		e.Ack(action.OpenMessageId, action.OpenId)
+		if next == nil {
+			return nil, nil
+		}
+		return next(r, action)
+	}
+}
+
+// Stop 停止所有进行中的升级加急
+func (e *Escalator) Stop() {
+	e.mu.Lock()
+	pending := make([]*Escalation, 0, len(e.pending))
+	for _, es := range e.pending {
+		pending = append(pending, es)
+	}
+	e.mu.Unlock()
+	for _, es := range pending {
+		es.Stop()
+	}
+}
+
+// Stop 停止升级加急 (不视为确认)
+func (es *Escalation) Stop() {
+	es.finish(false, "")
+}
+
+// Done 在升级加急结束 (之后不会再有加急请求) 时关闭
+func (es *Escalation) Done() <-chan struct{} {
+	return es.done
+}
+
+// Acked 返回消息是否已被确认及确认者
+func (es *Escalation) Acked() (acked bool, by string) {
+	es.mu.Lock()
+	defer es.mu.Unlock()
+	return es.acked, es.ackedBy
+}
+
+// finish 结束升级加急, 只有第一次调用生效并返回 true
+func (es *Escalation) finish(acked bool, by string) bool {
+	finished := false
+	es.once.Do(func() {
+		es.mu.Lock()
+		es.acked = acked
+		es.ackedBy = by
+		es.mu.Unlock()
+		close(es.stop)
+		es.cancel()
+		finished = true
+	})
+	return finished
+}
+
+func (ctx detachedContext) Deadline() (deadline time.Time, ok bool) { return }
+func (ctx detachedContext) Done() <-chan struct{}                   { return nil }
+func (ctx detachedContext) Err() error                              { return nil }
+func (ctx detachedContext) Value(key interface{}) interface{}       { return ctx.parent.Value(key) }
+
+func containsString(strs []string, s string) bool {
+	for _, str := range strs {
+		if str == s {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,53 @@
+package message
+
+import (
+	"fmt"
+
+	"github.com/huangjunwen/golibs/logr"
+)
+
+// EscalatorOption 是创建 Escalator 的选项
+type EscalatorOption func(*Escalator) error
+
+// ESSteps 设置升级加急步骤, 默认为 DefaultEscalationSteps; 步骤需按 After 升序排列
+func ESSteps(steps ...EscalationStep) EscalatorOption {
+	return func(e *Escalator) error {
+		if len(steps) == 0 {
+			return fmt.Errorf("ESSteps: empty steps")
+		}
+		for i, step := range steps {
+			switch step.Type {
+			case UrgentTypeApp, UrgentTypeSMS, UrgentTypePhone:
+			default:
+				return fmt.Errorf("ESSteps: unsupported urgent type %q", step.Type)
+			}
+			if i > 0 && step.After < steps[i-1].After {
+				return fmt.Errorf("ESSteps: steps must be sorted by After")
+			}
+		}
+		e.steps = steps
+		return nil
+	}
+}
+
+// ESOnStep 在每一步加急后回调, err 非 nil 表示加急失败 (此时 result 可能为 nil)
+func ESOnStep(fn func(es *Escalation, step EscalationStep, result *UrgentResult, err error)) EscalatorOption {
+	return func(e *Escalator) error {
+		if fn == nil {
+			fn = func(*Escalation, EscalationStep, *UrgentResult, error) {}
+		}
+		e.onStep = fn
+		return nil
+	}
+}
+
+// ESLogger 设置日志
+func ESLogger(logger logr.Logger) EscalatorOption {
+	return func(e *Escalator) error {
+		if logger == nil {
+			logger = logr.Nop
+		}
+		e.logger = logger
+		return nil
+	}
+}
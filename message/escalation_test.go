@@ -0,0 +1,190 @@
+package message
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/huangjunwen/feishu-driver/conf"
+	"github.com/huangjunwen/feishu-driver/utils"
+	"github.com/huangjunwen/feishu-driver/webhook"
+	"github.com/huangjunwen/feishu-driver/webhook/events"
+)
+
+func TestEscalation(t *testing.T) {
+	assert := assert.New(t)
+
+	srv, reqs := newIMTestServer(func(r *http.Request) string {
+		if r.Method == "POST" {
+			return `{"code": 0, "data": {"message_id": "om_1"}}`
+		}
+		return `{"code": 0, "data": {"invalid_user_id_list": []}}`
+	})
+	defer srv.Close()
+	ctx := utils.APIOptions{URLBase: srv.URL}.WithCtx(context.Background())
+	provider := conf.TenantAccessTokenProviderFunc(func() (string, error) { return "t-token", nil })
+	// reset 返回上次调用以来的加急请求, 需要在加急结束后调用
+	reset := func() []string {
+		uris := []string{}
+		for _, r := range *reqs {
+			if r.Method != "POST" {
+				uris = append(uris, r.URI)
+			}
+		}
+		*reqs = nil
+		return uris
+	}
+	req := CreateMessage{
+		ReceiveIdType: ReceiveIdTypeChatId,
+		ReceiveId:     "oc_1",
+		Content:       &SendTextContent{Text: "alert"},
+	}
+
+	// 直接加急
+	{
+		_, err := Urgent{MessageId: "om_1", Type: "email", UserIds: []string{"ou_1"}}.Do(ctx, provider)
+		assert.Error(err)
+		res, err := Urgent{MessageId: "om_1", Type: UrgentTypeSMS, UserIds: []string{"ou_1"}}.Do(ctx, provider)
+		assert.NoError(err)
+		assert.NoError(res.ResultError())
+		assert.JSONEq(`{"user_id_list": ["ou_1"]}`, (*reqs)[0].Body)
+		assert.Equal([]string{"/im/v1/messages/om_1/urgent_sms?user_id_type=open_id"}, reset())
+	}
+
+	_, err := NewEscalator(provider, ESSteps(EscalationStep{Type: "email"}))
+	assert.Error(err)
+
+	steps := ESSteps(
+		EscalationStep{Type: UrgentTypeApp},
+		EscalationStep{Type: UrgentTypeSMS, After: 50 * time.Millisecond},
+		EscalationStep{Type: UrgentTypePhone, After: 10 * time.Second},
+	)
+	wait := func(es *Escalation) {
+		select {
+		case <-es.Done():
+		case <-time.After(5 * time.Second):
+			t.Fatal("escalation not done")
+		}
+	}
+
+	// 已读确认
+	{
+		stepped := make(chan string, 3)
+		e, err := NewEscalator(provider, steps, ESOnStep(func(es *Escalation, step EscalationStep, result *UrgentResult, err error) {
+			stepped <- step.Type
+		}))
+		assert.NoError(err)
+
+		es, err := e.Start(ctx, req, []string{"ou_1"})
+		assert.NoError(err)
+		assert.Equal("om_1", es.MessageId)
+		assert.Equal(UrgentTypeApp, <-stepped)
+		assert.Equal(UrgentTypeSMS, <-stepped)
+
+		// 非加急用户已读不算确认
+		ev := &events.MessageReadV1{MessageIdList: []string{"om_1"}}
+		ev.Reader.ReaderId.OpenId = "ou_2"
+		handled, err := e.HandleEvent(ctx, ev)
+		assert.True(handled)
+		assert.NoError(err)
+		acked, _ := es.Acked()
+		assert.False(acked)
+
+		ev.Reader.ReaderId.OpenId = "ou_1"
+		e.HandleEvent(ctx, ev)
+		wait(es)
+		acked, by := es.Acked()
+		assert.True(acked)
+		assert.Equal("ou_1", by)
+		assert.Equal([]string{
+			"/im/v1/messages/om_1/urgent_app?user_id_type=open_id",
+			"/im/v1/messages/om_1/urgent_sms?user_id_type=open_id",
+		}, reset())
+		assert.False(e.Ack("om_1", "ou_1"))
+	}
+
+	// 卡片交互确认
+	{
+		e, err := NewEscalator(provider, steps)
+		assert.NoError(err)
+		es, err := e.Start(ctx, req, []string{"ou_1"})
+		assert.NoError(err)
+
+		handler := e.WrapCardActionHandler(func(r *http.Request, action *webhook.CardAction) (interface{}, error) {
+			return "updated", nil
+		})
+		action := &webhook.CardAction{OpenId: "ou_3", OpenMessageId: "om_1"}
+		card, err := handler(nil, action)
+		assert.NoError(err)
+		assert.Equal("updated", card)
+		wait(es)
+		acked, by := es.Acked()
+		assert.True(acked)
+		assert.Equal("ou_3", by)
+		reset()
+	}
+
+	// 步骤执行完毕未确认
+	{
+		e, err := NewEscalator(provider, ESSteps(
+			EscalationStep{Type: UrgentTypeApp},
+			EscalationStep{Type: UrgentTypePhone, After: 10 * time.Millisecond},
+		))
+		assert.NoError(err)
+		es, err := e.Start(ctx, req, []string{"ou_1"})
+		assert.NoError(err)
+		wait(es)
+		acked, _ := es.Acked()
+		assert.False(acked)
+		assert.Len(reset(), 2)
+	}
+
+	// 确认时下一步的计时器已到期, 不再加急
+	for i := 0; i < 20; i++ {
+		var e *Escalator
+		stepped := make(chan string, 3)
+		e, err := NewEscalator(provider, ESSteps(
+			EscalationStep{Type: UrgentTypeApp},
+			EscalationStep{Type: UrgentTypeSMS},
+		), ESOnStep(func(es *Escalation, step EscalationStep, result *UrgentResult, err error) {
+			stepped <- step.Type
+			e.Ack(es.MessageId, "ou_1")
+		}))
+		assert.NoError(err)
+		es, err := e.Start(ctx, req, []string{"ou_1"})
+		assert.NoError(err)
+		wait(es)
+		close(stepped)
+		types := []string{}
+		for typ := range stepped {
+			types = append(types, typ)
+		}
+		assert.Equal([]string{UrgentTypeApp}, types)
+		reset()
+	}
+
+	// Start 的 ctx 取消后加急仍继续 (例如使用 http 请求的 ctx), 直到 Stop
+	{
+		stepped := make(chan string, 3)
+		e, err := NewEscalator(provider, steps, ESOnStep(func(es *Escalation, step EscalationStep, result *UrgentResult, err error) {
+			assert.NoError(err)
+			stepped <- step.Type
+		}))
+		assert.NoError(err)
+		cctx, cancel := context.WithCancel(ctx)
+		es, err := e.Start(cctx, req, []string{"ou_1"})
+		assert.NoError(err)
+		cancel()
+		assert.Equal(UrgentTypeApp, <-stepped)
+		assert.Equal(UrgentTypeSMS, <-stepped)
+
+		e.Stop()
+		wait(es)
+		acked, _ := es.Acked()
+		assert.False(acked)
+		assert.Len(reset(), 2)
+	}
+}
@@ -0,0 +1,32 @@
+package message
+
+import (
+	"encoding/json"
+)
+
+// encodeContent 将 SendContent 编码为 im/v1 接口中 content 字段使用的 json 字符串,
+// 与 message/v4 接口相比，富文本没有外层的 post，群名片使用 chat_id，卡片直接作为 content
+func encodeContent(content SendContent) (string, error) {
+	var v interface{} = content
+	switch c := content.(type) {
+	case *SendPostContent:
+		v = c.Post
+	case *SendShareChatContent:
+		v = map[string]string{"chat_id": c.ShareOpenChatId}
+	case cardContent:
+		v = c.sendCard()
+	}
+	b, err := json.Marshal(v)
+	if err != nil {
+		return "", err
+	}
+	return string(b), nil
+}
+
+// validateContent 在发送前校验 content
+func validateContent(content SendContent) error {
+	if v, ok := content.(contentValidator); ok {
+		return v.Validate()
+	}
+	return nil
+}
@@ -0,0 +1,324 @@
+package message
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strconv"
+
+	"github.com/huangjunwen/feishu-driver/conf"
+	"github.com/huangjunwen/feishu-driver/utils"
+	"github.com/huangjunwen/feishu-driver/webhook/events"
+)
+
+var (
+	ReceiveIdTypeOpenId  = "open_id"
+	ReceiveIdTypeUserId  = "user_id"
+	ReceiveIdTypeUnionId = "union_id"
+	ReceiveIdTypeEmail   = "email"
+	ReceiveIdTypeChatId  = "chat_id"
+)
+
+// Message 是 im/v1 接口返回的消息
+type Message struct {
+	MessageId      string `json:"message_id"`
+	RootId         string `json:"root_id"`
+	ParentId       string `json:"parent_id"`
+	ThreadId       string `json:"thread_id"`
+	MsgType        string `json:"msg_type"`
+	CreateTime     string `json:"create_time"`
+	UpdateTime     string `json:"update_time"`
+	Deleted        bool   `json:"deleted"`
+	Updated        bool   `json:"updated"`
+	ChatId         string `json:"chat_id"`
+	UpperMessageId string `json:"upper_message_id"`
+	Sender         struct {
+		Id         string `json:"id"`
+		IdType     string `json:"id_type"`
+		SenderType string `json:"sender_type"`
+		TenantKey  string `json:"tenant_key"`
+	} `json:"sender"`
+	Body struct {
+		Content string `json:"content"`
+	} `json:"body"`
+	Mentions []struct {
+		Key       string `json:"key"`
+		Id        string `json:"id"`
+		IdType    string `json:"id_type"`
+		Name      string `json:"name"`
+		TenantKey string `json:"tenant_key"`
+	} `json:"mentions"`
+}
+
+// MessageResult 是发送/回复消息接口的结果
+type MessageResult struct {
+	utils.APIResultBase
+
+	Data *Message `json:"data"`
+}
+
+// CreateMessage 发送消息 (im/v1)
+type CreateMessage struct {
+	// ReceiveIdType 是 ReceiveId 的类型: open_id/user_id/union_id/email/chat_id
+	ReceiveIdType string `json:"-"`
+
+	ReceiveId string `json:"receive_id"`
+
+	// Content 是实际内容
+	Content SendContent `json:"-"`
+
+	// UUID 用于请求去重, 可为空
+	UUID string `json:"uuid,omitempty"`
+}
+
+// ReplyMessage 回复消息 (im/v1)
+type ReplyMessage struct {
+	// MessageId 是被回复的消息
+	MessageId string `json:"-"`
+
+	// Content 是实际内容
+	Content SendContent `json:"-"`
+
+	// UUID 用于请求去重, 可为空
+	UUID string `json:"uuid,omitempty"`
+}
+
+// UpdateMessage 编辑消息 (im/v1), 仅支持文本及富文本消息
+type UpdateMessage struct {
+	MessageId string
+
+	// Content 是新的内容, 必须是 *SendTextContent 或 *SendPostContent
+	Content SendContent
+}
+
+// PatchMessage 更新已发送的消息卡片 (im/v1)
+type PatchMessage struct {
+	MessageId string
+
+	// Content 是新的卡片, 必须是 *SendCardContent 或 *SendTemplateCardContent
+	Content SendContent
+}
+
+// ListMessages 获取会话历史消息 (im/v1)
+type ListMessages struct {
+	// ChatId 是会话 id
+	ChatId string
+
+	// StartTime/EndTime 是消息创建时间范围 (秒级时间戳), 0 表示不限
+	StartTime int64
+	EndTime   int64
+
+	// SortDesc 为 true 时按创建时间降序排列, 默认升序
+	SortDesc bool
+
+	// PageSize 是分页大小, 0 表示使用默认值
+	PageSize int
+
+	// PageToken 是分页标记, 第一页为空
+	PageToken string
+}
+
+// GetMessageResult 是获取消息接口的结果
+type GetMessageResult struct {
+	utils.APIResultBase
+
+	Data struct {
+		Items []*Message `json:"items"`
+	} `json:"data"`
+}
+
+// ListMessagesResult 是获取会话历史消息接口的结果
+type ListMessagesResult struct {
+	utils.APIResultBase
+
+	Data struct {
+		HasMore   bool       `json:"has_more"`
+		PageToken string     `json:"page_token"`
+		Items     []*Message `json:"items"`
+	} `json:"data"`
+}
+
+// ParseContent 根据消息类型解析消息内容, 见 events.ParseMessageContent
+func (msg *Message) ParseContent() (events.MessageContent, error) {
+	return events.ParseMessageContent(msg.MsgType, msg.Body.Content)
+}
+
+// Do 调用 api
+func (req CreateMessage) Do(ctx context.Context, provider conf.TenantAccessTokenProvider) (*MessageResult, error) {
+	if req.ReceiveIdType == "" {
+		return nil, fmt.Errorf("Missing receive id type")
+	}
+	body, err := newMessageBody(req.Content)
+	if err != nil {
+		return nil, err
+	}
+	body.ReceiveId = req.ReceiveId
+	body.UUID = req.UUID
+
+	result := &MessageResult{}
+	err = utils.PostJSONWithTenantAccessToken(ctx, "/im/v1/messages?receive_id_type="+url.QueryEscape(req.ReceiveIdType), provider, body, result)
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// Do 调用 api
+func (req ReplyMessage) Do(ctx context.Context, provider conf.TenantAccessTokenProvider) (*MessageResult, error) {
+	if req.MessageId == "" {
+		return nil, fmt.Errorf("Missing message id")
+	}
+	body, err := newMessageBody(req.Content)
+	if err != nil {
+		return nil, err
+	}
+	body.UUID = req.UUID
+
+	result := &MessageResult{}
+	err = utils.PostJSONWithTenantAccessToken(ctx, "/im/v1/messages/"+url.PathEscape(req.MessageId)+"/reply", provider, body, result)
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// Do 调用 api
+func (req UpdateMessage) Do(ctx context.Context, provider conf.TenantAccessTokenProvider) (*MessageResult, error) {
+	if req.MessageId == "" {
+		return nil, fmt.Errorf("Missing message id")
+	}
+	switch req.Content.(type) {
+	case *SendTextContent, *SendPostContent:
+	default:
+		return nil, fmt.Errorf("UpdateMessage only support text or post content")
+	}
+	body, err := newMessageBody(req.Content)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &MessageResult{}
+	err = utils.PutJSONWithTenantAccessToken(ctx, "/im/v1/messages/"+url.PathEscape(req.MessageId), provider, body, result)
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// Do 调用 api
+func (req PatchMessage) Do(ctx context.Context, provider conf.TenantAccessTokenProvider) (*utils.APIResultBase, error) {
+	if req.MessageId == "" {
+		return nil, fmt.Errorf("Missing message id")
+	}
+	if _, ok := req.Content.(cardContent); !ok {
+		return nil, fmt.Errorf("PatchMessage only support card content")
+	}
+	body, err := newMessageBody(req.Content)
+	if err != nil {
+		return nil, err
+	}
+	body.MsgType = ""
+
+	result := &utils.APIResultBase{}
+	err = utils.PatchJSONWithTenantAccessToken(ctx, "/im/v1/messages/"+url.PathEscape(req.MessageId), provider, body, result)
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// RecallMessage 撤回消息 (im/v1)
+func RecallMessage(ctx context.Context, provider conf.TenantAccessTokenProvider, messageId string) (*utils.APIResultBase, error) {
+	result := &utils.APIResultBase{}
+	err := utils.DeleteJSONWithTenantAccessToken(ctx, "/im/v1/messages/"+url.PathEscape(messageId), provider, nil, result)
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// GetMessage 获取指定消息的内容 (im/v1), 合并转发消息会同时返回子消息
+func GetMessage(ctx context.Context, provider conf.TenantAccessTokenProvider, messageId string) (*GetMessageResult, error) {
+	result := &GetMessageResult{}
+	err := utils.GetJSONWithTenantAccessToken(ctx, "/im/v1/messages/"+url.PathEscape(messageId), provider, nil, result)
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// Do 调用 api, 获取一页消息
+func (req ListMessages) Do(ctx context.Context, provider conf.TenantAccessTokenProvider) (*ListMessagesResult, error) {
+	params := url.Values{}
+	params.Set("container_id_type", "chat")
+	params.Set("container_id", req.ChatId)
+	if req.StartTime != 0 {
+		params.Set("start_time", strconv.FormatInt(req.StartTime, 10))
+	}
+	if req.EndTime != 0 {
+		params.Set("end_time", strconv.FormatInt(req.EndTime, 10))
+	}
+	if req.SortDesc {
+		params.Set("sort_type", "ByCreateTimeDesc")
+	}
+	if req.PageSize != 0 {
+		params.Set("page_size", strconv.Itoa(req.PageSize))
+	}
+	if req.PageToken != "" {
+		params.Set("page_token", req.PageToken)
+	}
+
+	result := &ListMessagesResult{}
+	err := utils.GetJSONWithTenantAccessToken(ctx, "/im/v1/messages", provider, params, result)
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// Each 从 req.PageToken 开始逐页获取消息并依次交给 fn 处理, fn 返回错误时停止并返回该错误
+func (req ListMessages) Each(ctx context.Context, provider conf.TenantAccessTokenProvider, fn func(msg *Message) error) error {
+	for {
+		result, err := req.Do(ctx, provider)
+		if err != nil {
+			return err
+		}
+		if err := result.ResultError(); err != nil {
+			return err
+		}
+		for _, msg := range result.Data.Items {
+			if err := fn(msg); err != nil {
+				return err
+			}
+		}
+		if !result.Data.HasMore || result.Data.PageToken == "" {
+			return nil
+		}
+		req.PageToken = result.Data.PageToken
+	}
+}
+
+// messageBody 是 im/v1 发送/回复/编辑消息接口的请求 body
+type messageBody struct {
+	ReceiveId string `json:"receive_id,omitempty"`
+	MsgType   string `json:"msg_type,omitempty"`
+	Content   string `json:"content"`
+	UUID      string `json:"uuid,omitempty"`
+}
+
+func newMessageBody(content SendContent) (*messageBody, error) {
+	if content == nil {
+		return nil, fmt.Errorf("Missing content")
+	}
+	if err := validateContent(content); err != nil {
+		return nil, err
+	}
+	encoded, err := encodeContent(content)
+	if err != nil {
+		return nil, err
+	}
+	return &messageBody{
+		MsgType: content.SendContentMsgType(),
+		Content: encoded,
+	}, nil
+}
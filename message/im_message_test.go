@@ -0,0 +1,177 @@
+package message
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/huangjunwen/feishu-driver/conf"
+	"github.com/huangjunwen/feishu-driver/message/card"
+	"github.com/huangjunwen/feishu-driver/utils"
+	"github.com/huangjunwen/feishu-driver/webhook/events"
+)
+
+const testMessage = `{
+	"message_id": "om_1",
+	"root_id": "om_0",
+	"msg_type": "text",
+	"create_time": "1615380573411",
+	"chat_id": "oc_1",
+	"sender": {"id": "cli_1", "id_type": "app_id", "sender_type": "app", "tenant_key": "736588c9260f175e"},
+	"body": {"content": "{\"text\":\"@_user_1 hi\"}"},
+	"mentions": [{"key": "@_user_1", "id": "ou_1", "id_type": "open_id", "name": "Tom"}]
+}`
+
+type recordedRequest struct {
+	Method string
+	URI    string
+	Body   string
+}
+
+func newIMTestServer(respond func(r *http.Request) string) (*httptest.Server, *[]recordedRequest) {
+	// 请求可能并发到达 (例如后台的加急), 记录在响应之前完成, 因此可在请求返回后读取
+	mu := &sync.Mutex{}
+	reqs := &[]recordedRequest{}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := ioutil.ReadAll(r.Body)
+		mu.Lock()
+		*reqs = append(*reqs, recordedRequest{Method: r.Method, URI: r.URL.RequestURI(), Body: string(body)})
+		mu.Unlock()
+		w.Write([]byte(respond(r)))
+	}))
+	return srv, reqs
+}
+
+func TestIMMessage(t *testing.T) {
+	assert := assert.New(t)
+
+	srv, reqs := newIMTestServer(func(r *http.Request) string {
+		switch {
+		case r.Method == "GET" && r.URL.Path == "/im/v1/messages":
+			if r.URL.Query().Get("page_token") == "" {
+				return fmt.Sprintf(`{"code": 0, "data": {"has_more": true, "page_token": "p2", "items": [%s]}}`, testMessage)
+			}
+			return fmt.Sprintf(`{"code": 0, "data": {"has_more": false, "items": [%s, %s]}}`, testMessage, testMessage)
+		case r.Method == "GET":
+			return fmt.Sprintf(`{"code": 0, "data": {"items": [%s]}}`, testMessage)
+		case r.Method == "POST" || r.Method == "PUT":
+			return fmt.Sprintf(`{"code": 0, "data": %s}`, testMessage)
+		}
+		return `{"code": 0, "msg": "success"}`
+	})
+	defer srv.Close()
+	ctx := utils.APIOptions{URLBase: srv.URL}.WithCtx(context.Background())
+	provider := conf.TenantAccessTokenProviderFunc(func() (string, error) { return "t-token", nil })
+	last := func() recordedRequest { return (*reqs)[len(*reqs)-1] }
+
+	// 发送
+	{
+		res, err := CreateMessage{
+			ReceiveIdType: ReceiveIdTypeEmail,
+			ReceiveId:     "a@example.com",
+			Content:       &SendTextContent{Text: "hi"},
+			UUID:          "u1",
+		}.Do(ctx, provider)
+		assert.NoError(err)
+		assert.NoError(res.ResultError())
+		assert.Equal("om_1", res.Data.MessageId)
+		assert.Equal("POST", last().Method)
+		assert.Equal("/im/v1/messages?receive_id_type=email", last().URI)
+		assert.JSONEq(`{"receive_id": "a@example.com", "msg_type": "text", "content": "{\"text\":\"hi\"}", "uuid": "u1"}`, last().Body)
+
+		c, err := res.Data.ParseContent()
+		assert.NoError(err)
+		assert.Equal("@_user_1 hi", c.(*events.TextContent).Text)
+		assert.Equal("Tom", res.Data.Mentions[0].Name)
+
+		_, err = CreateMessage{ReceiveId: "ou_1", Content: &SendTextContent{Text: "hi"}}.Do(ctx, provider)
+		assert.Error(err)
+	}
+
+	// 各类内容的编码
+	{
+		post, _ := NewPostBuilder().Title("t").Text("hi").Build()
+		for _, testCase := range []struct {
+			Content  SendContent
+			MsgType  string
+			Expected string
+		}{
+			{post, "post", `{"zh_cn":{"title":"t","content":[[{"tag":"text","text":"hi"}]]}}`},
+			{&SendImageContent{ImageKey: "img_1"}, "image", `{"image_key":"img_1"}`},
+			{&SendShareChatContent{ShareOpenChatId: "oc_2"}, "share_chat", `{"chat_id":"oc_2"}`},
+			{&SendCardContent{Card: card.New().Add(card.NewHr())}, "interactive", `{"config":{"wide_screen_mode":true,"enable_forward":true},"elements":[{"tag":"hr"}]}`},
+			{&SendTemplateCardContent{TemplateId: "ctp_1"}, "interactive", `{"type":"template","data":{"template_id":"ctp_1"}}`},
+		} {
+			_, err := CreateMessage{ReceiveIdType: ReceiveIdTypeChatId, ReceiveId: "oc_1", Content: testCase.Content}.Do(ctx, provider)
+			assert.NoError(err)
+			body := &messageBody{}
+			assert.NoError(json.Unmarshal([]byte(last().Body), body))
+			assert.Equal(testCase.MsgType, body.MsgType)
+			assert.JSONEq(testCase.Expected, body.Content)
+		}
+	}
+
+	// 回复
+	{
+		res, err := ReplyMessage{MessageId: "om_0", Content: &SendTextContent{Text: "re"}}.Do(ctx, provider)
+		assert.NoError(err)
+		assert.Equal("om_0", res.Data.RootId)
+		assert.Equal("/im/v1/messages/om_0/reply", last().URI)
+		assert.JSONEq(`{"msg_type": "text", "content": "{\"text\":\"re\"}"}`, last().Body)
+	}
+
+	// 编辑/更新卡片
+	{
+		_, err := UpdateMessage{MessageId: "om_1", Content: &SendTextContent{Text: "edited"}}.Do(ctx, provider)
+		assert.NoError(err)
+		assert.Equal(recordedRequest{"PUT", "/im/v1/messages/om_1", `{"msg_type":"text","content":"{\"text\":\"edited\"}"}` + "\n"}, last())
+		_, err = UpdateMessage{MessageId: "om_1", Content: &SendImageContent{ImageKey: "img_1"}}.Do(ctx, provider)
+		assert.Error(err)
+
+		res, err := PatchMessage{MessageId: "om_1", Content: &SendCardContent{Card: card.New().Add(card.NewHr())}}.Do(ctx, provider)
+		assert.NoError(err)
+		assert.NoError(res.ResultError())
+		assert.Equal("PATCH", last().Method)
+		assert.JSONEq(`{"content": "{\"config\":{\"wide_screen_mode\":true,\"enable_forward\":true},\"elements\":[{\"tag\":\"hr\"}]}"}`, last().Body)
+		_, err = PatchMessage{MessageId: "om_1", Content: &SendTextContent{Text: "x"}}.Do(ctx, provider)
+		assert.Error(err)
+	}
+
+	// 撤回/获取
+	{
+		res, err := RecallMessage(ctx, provider, "om_1")
+		assert.NoError(err)
+		assert.NoError(res.ResultError())
+		assert.Equal(recordedRequest{"DELETE", "/im/v1/messages/om_1", ""}, last())
+
+		getRes, err := GetMessage(ctx, provider, "om_1")
+		assert.NoError(err)
+		assert.Len(getRes.Data.Items, 1)
+		assert.Equal("GET", last().Method)
+	}
+
+	// 历史消息
+	{
+		n := len(*reqs)
+		ids := []string{}
+		err := ListMessages{ChatId: "oc_1", StartTime: 1, SortDesc: true, PageSize: 1}.Each(ctx, provider, func(msg *Message) error {
+			ids = append(ids, msg.MessageId)
+			return nil
+		})
+		assert.NoError(err)
+		assert.Equal([]string{"om_1", "om_1", "om_1"}, ids)
+		assert.Equal("/im/v1/messages?container_id=oc_1&container_id_type=chat&page_size=1&sort_type=ByCreateTimeDesc&start_time=1", (*reqs)[n].URI)
+		assert.Equal("/im/v1/messages?container_id=oc_1&container_id_type=chat&page_size=1&page_token=p2&sort_type=ByCreateTimeDesc&start_time=1", (*reqs)[n+1].URI)
+
+		stop := fmt.Errorf("stop")
+		err = ListMessages{ChatId: "oc_1"}.Each(ctx, provider, func(msg *Message) error { return stop })
+		assert.Equal(stop, err)
+	}
+}
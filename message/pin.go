@@ -0,0 +1,144 @@
+package message
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strconv"
+
+	"github.com/huangjunwen/feishu-driver/conf"
+	"github.com/huangjunwen/feishu-driver/utils"
+)
+
+// Pin 是会话中的置顶 (Pin) 消息
+type Pin struct {
+	MessageId      string `json:"message_id"`
+	ChatId         string `json:"chat_id"`
+	OperatorId     string `json:"operator_id"`
+	OperatorIdType string `json:"operator_id_type"`
+	// CreateTime 是 Pin 的时间 (毫秒级时间戳)
+	CreateTime string `json:"create_time"`
+}
+
+// PinResult 是 Pin 消息接口的结果
+type PinResult struct {
+	utils.APIResultBase
+
+	Data struct {
+		Pin *Pin `json:"pin"`
+	} `json:"data"`
+}
+
+// ListPins 获取会话中的 Pin 消息 (im/v1), 按 Pin 的时间降序排列
+type ListPins struct {
+	// ChatId 是会话 id
+	ChatId string
+
+	// StartTime/EndTime 是 Pin 时间范围 (毫秒级时间戳), 0 表示不限
+	StartTime int64
+	EndTime   int64
+
+	// PageSize 是分页大小, 0 表示使用默认值
+	PageSize int
+
+	// PageToken 是分页标记, 第一页为空
+	PageToken string
+}
+
+// ListPinsResult 是获取 Pin 消息接口的结果
+type ListPinsResult struct {
+	utils.APIResultBase
+
+	Data struct {
+		HasMore   bool   `json:"has_more"`
+		PageToken string `json:"page_token"`
+		Items     []*Pin `json:"items"`
+	} `json:"data"`
+}
+
+// PinMessage Pin 消息 (im/v1), 重复 Pin 时返回的 Pin 为 nil
+func PinMessage(ctx context.Context, provider conf.TenantAccessTokenProvider, messageId string) (*PinResult, error) {
+	body := map[string]string{
+		"message_id": messageId,
+	}
+	result := &PinResult{}
+	err := utils.PostJSONWithTenantAccessToken(ctx, "/im/v1/pins", provider, body, result)
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// UnpinMessage 移除 Pin 消息 (im/v1)
+func UnpinMessage(ctx context.Context, provider conf.TenantAccessTokenProvider, messageId string) (*utils.APIResultBase, error) {
+	result := &utils.APIResultBase{}
+	err := utils.DeleteJSONWithTenantAccessToken(ctx, "/im/v1/pins/"+url.PathEscape(messageId), provider, nil, result)
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// Message 获取 Pin 的消息, 可使用 Message.ParseContent 解析内容
+func (pin *Pin) Message(ctx context.Context, provider conf.TenantAccessTokenProvider) (*Message, error) {
+	result, err := GetMessage(ctx, provider, pin.MessageId)
+	if err != nil {
+		return nil, err
+	}
+	if err := result.ResultError(); err != nil {
+		return nil, err
+	}
+	for _, msg := range result.Data.Items {
+		if msg.MessageId == pin.MessageId {
+			return msg, nil
+		}
+	}
+	return nil, fmt.Errorf("Message %q not found", pin.MessageId)
+}
+
+// Do 调用 api, 获取一页 Pin 消息
+func (req ListPins) Do(ctx context.Context, provider conf.TenantAccessTokenProvider) (*ListPinsResult, error) {
+	params := url.Values{}
+	params.Set("chat_id", req.ChatId)
+	if req.StartTime != 0 {
+		params.Set("start_time", strconv.FormatInt(req.StartTime, 10))
+	}
+	if req.EndTime != 0 {
+		params.Set("end_time", strconv.FormatInt(req.EndTime, 10))
+	}
+	if req.PageSize != 0 {
+		params.Set("page_size", strconv.Itoa(req.PageSize))
+	}
+	if req.PageToken != "" {
+		params.Set("page_token", req.PageToken)
+	}
+
+	result := &ListPinsResult{}
+	err := utils.GetJSONWithTenantAccessToken(ctx, "/im/v1/pins", provider, params, result)
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// Each 从 req.PageToken 开始逐页获取 Pin 消息并依次交给 fn 处理, fn 返回错误时停止并返回该错误
+func (req ListPins) Each(ctx context.Context, provider conf.TenantAccessTokenProvider, fn func(pin *Pin) error) error {
+	for {
+		result, err := req.Do(ctx, provider)
+		if err != nil {
+			return err
+		}
+		if err := result.ResultError(); err != nil {
+			return err
+		}
+		for _, pin := range result.Data.Items {
+			if err := fn(pin); err != nil {
+				return err
+			}
+		}
+		if !result.Data.HasMore || result.Data.PageToken == "" {
+			return nil
+		}
+		req.PageToken = result.Data.PageToken
+	}
+}
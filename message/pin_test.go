@@ -0,0 +1,90 @@
+package message
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/huangjunwen/feishu-driver/conf"
+	"github.com/huangjunwen/feishu-driver/utils"
+	"github.com/huangjunwen/feishu-driver/webhook/events"
+)
+
+func TestPinAndAnnouncement(t *testing.T) {
+	assert := assert.New(t)
+
+	const pin = `{"message_id": "om_1", "chat_id": "oc_1", "operator_id": "ou_1", "operator_id_type": "open_id", "create_time": "1615380573411"}`
+	srv, reqs := newIMTestServer(func(r *http.Request) string {
+		switch {
+		case r.URL.Path == "/im/v1/pins" && r.Method == "GET":
+			return `{"code": 0, "data": {"has_more": false, "items": [` + pin + `]}}`
+		case r.URL.Path == "/im/v1/pins":
+			return `{"code": 0, "data": {"pin": ` + pin + `}}`
+		case r.URL.Path == "/im/v1/messages/om_1":
+			return fmt.Sprintf(`{"code": 0, "data": {"items": [%s]}}`, testMessage)
+		case r.URL.Path == "/im/v1/chats/oc_1/announcement" && r.Method == "GET":
+			return `{"code": 0, "data": {"content": "{}", "revision": "12", "owner_id": "ou_1"}}`
+		}
+		return `{"code": 0, "msg": "success"}`
+	})
+	defer srv.Close()
+	ctx := utils.APIOptions{URLBase: srv.URL}.WithCtx(context.Background())
+	provider := conf.TenantAccessTokenProviderFunc(func() (string, error) { return "t-token", nil })
+	last := func() recordedRequest { return (*reqs)[len(*reqs)-1] }
+
+	// Pin
+	{
+		res, err := PinMessage(ctx, provider, "om_1")
+		assert.NoError(err)
+		assert.Equal("oc_1", res.Data.Pin.ChatId)
+		assert.JSONEq(`{"message_id": "om_1"}`, last().Body)
+
+		_, err = UnpinMessage(ctx, provider, "om_1")
+		assert.NoError(err)
+		assert.Equal("DELETE", last().Method)
+		assert.Equal("/im/v1/pins/om_1", last().URI)
+	}
+
+	// 获取 Pin 消息及其内容
+	{
+		pins := []*Pin{}
+		err := ListPins{ChatId: "oc_1", StartTime: 1615380573000}.Each(ctx, provider, func(pin *Pin) error {
+			pins = append(pins, pin)
+			return nil
+		})
+		assert.NoError(err)
+		assert.Equal("/im/v1/pins?chat_id=oc_1&start_time=1615380573000", last().URI)
+		if assert.Len(pins, 1) {
+			msg, err := pins[0].Message(ctx, provider)
+			assert.NoError(err)
+			c, err := msg.ParseContent()
+			assert.NoError(err)
+			assert.IsType(&events.TextContent{}, c)
+		}
+	}
+
+	// 群公告
+	{
+		_, err := PatchChatAnnouncement{ChatId: "oc_1", Requests: []*AnnouncementRequest{NewDeleteContentRangeRequest(0, 1)}}.Do(ctx, provider)
+		assert.Error(err)
+
+		n := len(*reqs)
+		err = EditChatAnnouncement(ctx, provider, "oc_1", func(a *ChatAnnouncement) ([]*AnnouncementRequest, error) {
+			return nil, nil
+		})
+		assert.NoError(err)
+		assert.Equal(n+1, len(*reqs))
+
+		err = EditChatAnnouncement(ctx, provider, "oc_1", func(a *ChatAnnouncement) ([]*AnnouncementRequest, error) {
+			assert.Equal("ou_1", a.OwnerId)
+			return []*AnnouncementRequest{NewDeleteContentRangeRequest(0, 1)}, nil
+		})
+		assert.NoError(err)
+		assert.Equal("PATCH", last().Method)
+		assert.Equal("/im/v1/chats/oc_1/announcement", last().URI)
+		assert.JSONEq(`{"revision": "12", "requests": ["{\"requestType\":\"DeleteContentRangeRequestType\",\"deleteContentRangeRequest\":{\"deleteRange\":{\"zoneId\":\"0\",\"startIndex\":0,\"endIndex\":1}}}"]}`, last().Body)
+	}
+}
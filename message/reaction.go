@@ -0,0 +1,151 @@
+package message
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strconv"
+
+	"github.com/huangjunwen/feishu-driver/conf"
+	"github.com/huangjunwen/feishu-driver/utils"
+)
+
+var (
+	// EmojiTypeThumbsUp 等是常用的表情类型, 完整列表见 https://open.feishu.cn/document/uAjLw4CM/ukTMukTMukTM/reference/im-v1/message-reaction/emojis-introduce
+	EmojiTypeThumbsUp = "THUMBSUP"
+	EmojiTypeOK       = "OK"
+	EmojiTypeDone     = "DONE"
+	EmojiTypeSmile    = "SMILE"
+	EmojiTypeHeart    = "HEART"
+)
+
+// Reaction 是消息的表情回复
+type Reaction struct {
+	ReactionId string `json:"reaction_id"`
+	Operator   struct {
+		// OperatorId 是操作人 id, OperatorType 为 app 时是 app_id
+		OperatorId string `json:"operator_id"`
+		// OperatorType 是操作人类型: user/app
+		OperatorType string `json:"operator_type"`
+	} `json:"operator"`
+	ActionTime   string `json:"action_time"`
+	ReactionType struct {
+		EmojiType string `json:"emoji_type"`
+	} `json:"reaction_type"`
+}
+
+// ReactionResult 是添加/删除表情回复接口的结果
+type ReactionResult struct {
+	utils.APIResultBase
+
+	Data *Reaction `json:"data"`
+}
+
+// ListReactions 获取消息的表情回复 (im/v1)
+type ListReactions struct {
+	// MessageId 是消息 id
+	MessageId string
+
+	// EmojiType 非空时只获取该类型的表情回复
+	EmojiType string
+
+	// UserIdType 是返回的 OperatorId 的类型: open_id/user_id/union_id, 为空时使用 open_id
+	UserIdType string
+
+	// PageSize 是分页大小, 0 表示使用默认值
+	PageSize int
+
+	// PageToken 是分页标记, 第一页为空
+	PageToken string
+}
+
+// ListReactionsResult 是获取消息表情回复接口的结果
+type ListReactionsResult struct {
+	utils.APIResultBase
+
+	Data struct {
+		HasMore   bool        `json:"has_more"`
+		PageToken string      `json:"page_token"`
+		Items     []*Reaction `json:"items"`
+	} `json:"data"`
+}
+
+// AddReaction 给消息添加表情回复 (im/v1)
+func AddReaction(ctx context.Context, provider conf.TenantAccessTokenProvider, messageId, emojiType string) (*ReactionResult, error) {
+	if emojiType == "" {
+		return nil, fmt.Errorf("Missing emoji type")
+	}
+	body := map[string]interface{}{
+		"reaction_type": map[string]string{
+			"emoji_type": emojiType,
+		},
+	}
+	result := &ReactionResult{}
+	err := utils.PostJSONWithTenantAccessToken(ctx, reactionsPath(messageId), provider, body, result)
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// DeleteReaction 删除消息的表情回复 (im/v1), 只能删除当前应用添加的表情回复
+func DeleteReaction(ctx context.Context, provider conf.TenantAccessTokenProvider, messageId, reactionId string) (*ReactionResult, error) {
+	result := &ReactionResult{}
+	err := utils.DeleteJSONWithTenantAccessToken(ctx, reactionsPath(messageId)+"/"+url.PathEscape(reactionId), provider, nil, result)
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// Do 调用 api, 获取一页表情回复
+func (req ListReactions) Do(ctx context.Context, provider conf.TenantAccessTokenProvider) (*ListReactionsResult, error) {
+	params := url.Values{}
+	if req.EmojiType != "" {
+		params.Set("reaction_type", req.EmojiType)
+	}
+	userIdType := req.UserIdType
+	if userIdType == "" {
+		userIdType = ReceiveIdTypeOpenId
+	}
+	params.Set("user_id_type", userIdType)
+	if req.PageSize != 0 {
+		params.Set("page_size", strconv.Itoa(req.PageSize))
+	}
+	if req.PageToken != "" {
+		params.Set("page_token", req.PageToken)
+	}
+
+	result := &ListReactionsResult{}
+	err := utils.GetJSONWithTenantAccessToken(ctx, reactionsPath(req.MessageId), provider, params, result)
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// Each 从 req.PageToken 开始逐页获取表情回复并依次交给 fn 处理, fn 返回错误时停止并返回该错误
+func (req ListReactions) Each(ctx context.Context, provider conf.TenantAccessTokenProvider, fn func(reaction *Reaction) error) error {
+	for {
+		result, err := req.Do(ctx, provider)
+		if err != nil {
+			return err
+		}
+		if err := result.ResultError(); err != nil {
+			return err
+		}
+		for _, reaction := range result.Data.Items {
+			if err := fn(reaction); err != nil {
+				return err
+			}
+		}
+		if !result.Data.HasMore || result.Data.PageToken == "" {
+			return nil
+		}
+		req.PageToken = result.Data.PageToken
+	}
+}
+
+func reactionsPath(messageId string) string {
+	return "/im/v1/messages/" + url.PathEscape(messageId) + "/reactions"
+}
@@ -0,0 +1,88 @@
+package message
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/huangjunwen/feishu-driver/conf"
+	"github.com/huangjunwen/feishu-driver/utils"
+)
+
+func TestReactionAndReadUsers(t *testing.T) {
+	assert := assert.New(t)
+
+	const reaction = `{
+		"reaction_id": "r_1",
+		"operator": {"operator_id": "ou_1", "operator_type": "user"},
+		"action_time": "1626086391570",
+		"reaction_type": {"emoji_type": "THUMBSUP"}
+	}`
+	srv, reqs := newIMTestServer(func(r *http.Request) string {
+		switch {
+		case r.URL.Path == "/im/v1/messages/om_1/read_users":
+			if r.URL.Query().Get("page_token") == "" {
+				return `{"code": 0, "data": {"has_more": true, "page_token": "p2", "items": [{"user_id_type": "open_id", "user_id": "ou_1", "timestamp": "1609484183000"}]}}`
+			}
+			return `{"code": 0, "data": {"has_more": false, "items": [{"user_id_type": "open_id", "user_id": "ou_2", "timestamp": "1609484184000"}]}}`
+		case r.Method == "GET":
+			return `{"code": 0, "data": {"has_more": false, "items": [` + reaction + `]}}`
+		}
+		return `{"code": 0, "data": ` + reaction + `}`
+	})
+	defer srv.Close()
+	ctx := utils.APIOptions{URLBase: srv.URL}.WithCtx(context.Background())
+	provider := conf.TenantAccessTokenProviderFunc(func() (string, error) { return "t-token", nil })
+	last := func() recordedRequest { return (*reqs)[len(*reqs)-1] }
+
+	// 已读用户
+	{
+		readers := []string{}
+		err := ListReadUsers{MessageId: "om_1"}.Each(ctx, provider, func(user *ReadUser) error {
+			readers = append(readers, user.UserId)
+			return nil
+		})
+		assert.NoError(err)
+		assert.Equal([]string{"ou_1", "ou_2"}, readers)
+		assert.Equal("/im/v1/messages/om_1/read_users?page_token=p2&user_id_type=open_id", last().URI)
+	}
+
+	// 添加表情回复
+	{
+		_, err := AddReaction(ctx, provider, "om_1", "")
+		assert.Error(err)
+
+		res, err := AddReaction(ctx, provider, "om_1", EmojiTypeThumbsUp)
+		assert.NoError(err)
+		assert.NoError(res.ResultError())
+		assert.Equal("r_1", res.Data.ReactionId)
+		assert.Equal("POST", last().Method)
+		assert.Equal("/im/v1/messages/om_1/reactions", last().URI)
+		assert.JSONEq(`{"reaction_type": {"emoji_type": "THUMBSUP"}}`, last().Body)
+	}
+
+	// 删除表情回复
+	{
+		res, err := DeleteReaction(ctx, provider, "om_1", "r_1")
+		assert.NoError(err)
+		assert.Equal("THUMBSUP", res.Data.ReactionType.EmojiType)
+		assert.Equal("DELETE", last().Method)
+		assert.Equal("/im/v1/messages/om_1/reactions/r_1", last().URI)
+	}
+
+	// 获取表情回复
+	{
+		reactions := []*Reaction{}
+		err := ListReactions{MessageId: "om_1", EmojiType: EmojiTypeThumbsUp, PageSize: 20}.Each(ctx, provider, func(r *Reaction) error {
+			reactions = append(reactions, r)
+			return nil
+		})
+		assert.NoError(err)
+		if assert.Len(reactions, 1) {
+			assert.Equal("ou_1", reactions[0].Operator.OperatorId)
+		}
+		assert.Equal("/im/v1/messages/om_1/reactions?page_size=20&reaction_type=THUMBSUP&user_id_type=open_id", last().URI)
+	}
+}
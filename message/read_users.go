@@ -0,0 +1,90 @@
+package message
+
+import (
+	"context"
+	"net/url"
+	"strconv"
+
+	"github.com/huangjunwen/feishu-driver/conf"
+	"github.com/huangjunwen/feishu-driver/utils"
+)
+
+// ListReadUsers 查询消息已读信息 (im/v1), 只能查询机器人自己发送的, 7 天内的消息
+type ListReadUsers struct {
+	// MessageId 是消息 id
+	MessageId string
+
+	// UserIdType 是返回的用户 id 的类型: open_id/user_id/union_id, 为空时使用 open_id
+	UserIdType string
+
+	// PageSize 是分页大小, 0 表示使用默认值
+	PageSize int
+
+	// PageToken 是分页标记, 第一页为空
+	PageToken string
+}
+
+// ReadUser 是已读消息的用户
+type ReadUser struct {
+	UserIdType string `json:"user_id_type"`
+	UserId     string `json:"user_id"`
+	// Timestamp 是阅读时间 (毫秒级时间戳)
+	Timestamp string `json:"timestamp"`
+	TenantKey string `json:"tenant_key"`
+}
+
+// ListReadUsersResult 是查询消息已读信息接口的结果
+type ListReadUsersResult struct {
+	utils.APIResultBase
+
+	Data struct {
+		HasMore   bool        `json:"has_more"`
+		PageToken string      `json:"page_token"`
+		Items     []*ReadUser `json:"items"`
+	} `json:"data"`
+}
+
+// Do 调用 api, 获取一页已读用户
+func (req ListReadUsers) Do(ctx context.Context, provider conf.TenantAccessTokenProvider) (*ListReadUsersResult, error) {
+	params := url.Values{}
+	userIdType := req.UserIdType
+	if userIdType == "" {
+		userIdType = ReceiveIdTypeOpenId
+	}
+	params.Set("user_id_type", userIdType)
+	if req.PageSize != 0 {
+		params.Set("page_size", strconv.Itoa(req.PageSize))
+	}
+	if req.PageToken != "" {
+		params.Set("page_token", req.PageToken)
+	}
+
+	result := &ListReadUsersResult{}
+	err := utils.GetJSONWithTenantAccessToken(ctx, "/im/v1/messages/"+url.PathEscape(req.MessageId)+"/read_users", provider, params, result)
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// Each 从 req.PageToken 开始逐页获取已读用户并依次交给 fn 处理, fn 返回错误时停止并返回该错误
+func (req ListReadUsers) Each(ctx context.Context, provider conf.TenantAccessTokenProvider, fn func(user *ReadUser) error) error {
+	for {
+		result, err := req.Do(ctx, provider)
+		if err != nil {
+			return err
+		}
+		if err := result.ResultError(); err != nil {
+			return err
+		}
+		for _, user := range result.Data.Items {
+			if err := fn(user); err != nil {
+				return err
+			}
+		}
+		if !result.Data.HasMore || result.Data.PageToken == "" {
+			return nil
+		}
+		req.PageToken = result.Data.PageToken
+	}
+}
@@ -20,10 +20,13 @@ type Send struct {
 	RootId string `json:"root_id,omitempty"`
 
 	// Content 是实际内容
-	Content SendContent `json:"content"`
+	Content SendContent `json:"content,omitempty"`
 
 	// MsgType 是内容类型，不需要填写，由 Content.SendContentMsgType 获得
 	MsgType string `json:"msg_type"`
+
+	// Card 是卡片内容，不需要填写，Content 为 *SendCardContent/*SendTemplateCardContent 时由其获得
+	Card interface{} `json:"card,omitempty"`
 }
 
 // SendResult 是发消息接口的结果
@@ -40,7 +43,14 @@ func (send Send) Do(ctx context.Context, provider conf.TenantAccessTokenProvider
 	if send.Content == nil {
 		return nil, fmt.Errorf("Missing content")
 	}
+	if err := validateContent(send.Content); err != nil {
+		return nil, err
+	}
 	send.MsgType = send.Content.SendContentMsgType()
+	if c, ok := send.Content.(cardContent); ok {
+		send.Card = c.sendCard()
+		send.Content = nil
+	}
 	result := &SendResult{}
 	err := utils.PostJSONWithTenantAccessToken(ctx, "/message/v4/send", provider, send, result)
 	if err != nil {
@@ -0,0 +1,59 @@
+package message
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/huangjunwen/feishu-driver/message/card"
+)
+
+var (
+	_ cardContent = (*SendCardContent)(nil)
+	_ cardContent = (*SendTemplateCardContent)(nil)
+)
+
+// cardContent 由卡片类的 SendContent 实现, 发送时放在请求的 card 字段中而不是 content 字段中
+type cardContent interface {
+	sendCard() interface{}
+}
+
+// SendCardContent 代表消息卡片 (interactive), 发送时卡片放在请求的 card 字段中
+type SendCardContent struct {
+	Card *card.Card
+
+	// Raw 是已编码的卡片 json (例如 card.RenderTemplate 的结果), 与 Card 二选一
+	Raw json.RawMessage
+}
+
+// SendContentMsgType 返回 interactive
+func (c *SendCardContent) SendContentMsgType() string {
+	return "interactive"
+}
+
+// Validate 校验卡片
+func (c *SendCardContent) Validate() error {
+	if c.Card != nil && c.Raw != nil {
+		return fmt.Errorf("Card and Raw are mutually exclusive")
+	}
+	if c.Raw != nil {
+		if !json.Valid(c.Raw) {
+			return fmt.Errorf("Invalid raw card")
+		}
+		return nil
+	}
+	if c.Card == nil {
+		return fmt.Errorf("Missing card")
+	}
+	return c.Card.Validate()
+}
+
+func (c *SendCardContent) sendCard() interface{} {
+	if c.Raw != nil {
+		return c.Raw
+	}
+	return c.Card
+}
+
+func (c *SendTemplateCardContent) sendCard() interface{} {
+	return c
+}
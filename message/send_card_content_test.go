@@ -0,0 +1,44 @@
+package message
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/huangjunwen/feishu-driver/conf"
+	"github.com/huangjunwen/feishu-driver/message/card"
+	"github.com/huangjunwen/feishu-driver/utils"
+)
+
+func TestSendCard(t *testing.T) {
+	assert := assert.New(t)
+
+	srv, reqs := newIMTestServer(func(r *http.Request) string {
+		return `{"code": 0, "msg": "ok", "data": {"message_id": "om_1"}}`
+	})
+	defer srv.Close()
+	ctx := utils.APIOptions{URLBase: srv.URL}.WithCtx(context.Background())
+	provider := conf.TenantAccessTokenProviderFunc(func() (string, error) { return "t-token", nil })
+
+	c := card.New().WithTitle("告警", "red").Add(card.NewMarkdown("**svc** 错误"))
+	_, err := Send{ChatId: "oc_1", RootId: "om_0", Content: &SendCardContent{Card: c}}.Do(ctx, provider)
+	assert.NoError(err)
+	assert.JSONEq(`{
+		"chat_id": "oc_1",
+		"root_id": "om_0",
+		"msg_type": "interactive",
+		"card": {
+			"config": {"wide_screen_mode": true, "enable_forward": true},
+			"header": {"title": {"tag": "plain_text", "content": "告警"}, "template": "red"},
+			"elements": [{"tag": "markdown", "content": "**svc** 错误"}]
+		}
+	}`, (*reqs)[0].Body)
+
+	// 发送前校验
+	_, err = Send{ChatId: "oc_1", Content: &SendCardContent{Card: card.New()}}.Do(ctx, provider)
+	assert.Error(err)
+	_, err = Send{ChatId: "oc_1", Content: &SendCardContent{}}.Do(ctx, provider)
+	assert.Error(err)
+}
@@ -5,6 +5,11 @@ type SendContent interface {
 	SendContentMsgType() string
 }
 
+// contentValidator 由需要在发送前校验的 SendContent 实现
+type contentValidator interface {
+	Validate() error
+}
+
 // SendTextContent 代表文本消息
 type SendTextContent struct {
 	Text string `json:"text"`
@@ -0,0 +1,218 @@
+package message
+
+import (
+	"fmt"
+	"sort"
+)
+
+var (
+	// PostLocales 是富文本支持的语言
+	PostLocales = []string{"zh_cn", "en_us", "ja_jp"}
+)
+
+// SendPostContent 代表富文本消息, 可使用 NewPostBuilder 构建
+type SendPostContent struct {
+	// Post 是各语言的富文本内容, key 为语言 (见 PostLocales)
+	Post map[string]*PostLocaleContent `json:"post"`
+}
+
+// PostLocaleContent 是某一语言的富文本内容
+type PostLocaleContent struct {
+	Title string `json:"title"`
+
+	// Content 是段落列表, 每个段落由若干行内元素组成
+	Content [][]*PostElement `json:"content"`
+}
+
+// PostElement 是富文本中的行内元素, Tag 决定了需要填写的字段:
+//
+//	text -> Text (UnEscape 表示是否 unescape 解码)
+//	a    -> Text, Href
+//	at   -> UserId (open_id, 或 all 表示 @所有人)
+//	img  -> ImageKey, Width, Height
+type PostElement struct {
+	Tag      string `json:"tag"`
+	Text     string `json:"text,omitempty"`
+	UnEscape bool   `json:"un_escape,omitempty"`
+	Href     string `json:"href,omitempty"`
+	UserId   string `json:"user_id,omitempty"`
+	ImageKey string `json:"image_key,omitempty"`
+	Width    int    `json:"width,omitempty"`
+	Height   int    `json:"height,omitempty"`
+}
+
+// PostBuilder 用于构建 SendPostContent, 例如:
+//
+//	content, err := NewPostBuilder().
+//		Locale("zh_cn").Title("发布通知").
+//		Text("服务 svc 已发布到 ").Link("prod", "https://example.com").
+//		Paragraph().At("ou_xxx").Text(" 请关注").
+//		Locale("en_us").Title("Release").
+//		Text("svc is released").
+//		Build()
+//
+// 在调用 Locale 之前添加的内容属于 zh_cn
+type PostBuilder struct {
+	content *SendPostContent
+	current *PostLocaleContent
+}
+
+// NewPostBuilder 创建一个 PostBuilder
+func NewPostBuilder() *PostBuilder {
+	return &PostBuilder{
+		content: &SendPostContent{
+			Post: map[string]*PostLocaleContent{},
+		},
+	}
+}
+
+// Locale 切换到语言 locale (不存在时创建), 之后添加的内容属于该语言
+func (b *PostBuilder) Locale(locale string) *PostBuilder {
+	c, ok := b.content.Post[locale]
+	if !ok {
+		c = &PostLocaleContent{}
+		b.content.Post[locale] = c
+	}
+	b.current = c
+	return b
+}
+
+// Title 设置当前语言的标题
+func (b *PostBuilder) Title(title string) *PostBuilder {
+	b.locale().Title = title
+	return b
+}
+
+// Paragraph 开始一个新的段落
+func (b *PostBuilder) Paragraph() *PostBuilder {
+	c := b.locale()
+	c.Content = append(c.Content, []*PostElement{})
+	return b
+}
+
+// Text 添加文本
+func (b *PostBuilder) Text(text string) *PostBuilder {
+	return b.Element(&PostElement{Tag: "text", Text: text})
+}
+
+// Link 添加超链接
+func (b *PostBuilder) Link(text, href string) *PostBuilder {
+	return b.Element(&PostElement{Tag: "a", Text: text, Href: href})
+}
+
+// At 添加 @ 用户, openId 为用户的 open_id
+func (b *PostBuilder) At(openId string) *PostBuilder {
+	return b.Element(&PostElement{Tag: "at", UserId: openId})
+}
+
+// AtAll 添加 @所有人
+func (b *PostBuilder) AtAll() *PostBuilder {
+	return b.At("all")
+}
+
+// Image 添加图片, 图片需要单独成段
+func (b *PostBuilder) Image(imageKey string, width, height int) *PostBuilder {
+	return b.Element(&PostElement{Tag: "img", ImageKey: imageKey, Width: width, Height: height})
+}
+
+// Element 在当前段落中添加元素
+func (b *PostBuilder) Element(elem *PostElement) *PostBuilder {
+	c := b.locale()
+	if len(c.Content) == 0 {
+		c.Content = append(c.Content, []*PostElement{})
+	}
+	last := len(c.Content) - 1
+	c.Content[last] = append(c.Content[last], elem)
+	return b
+}
+
+// Build 校验并返回 SendPostContent
+func (b *PostBuilder) Build() (*SendPostContent, error) {
+	if err := b.content.Validate(); err != nil {
+		return nil, err
+	}
+	return b.content, nil
+}
+
+func (b *PostBuilder) locale() *PostLocaleContent {
+	if b.current == nil {
+		b.Locale(PostLocales[0])
+	}
+	return b.current
+}
+
+// SendContentMsgType 返回 post
+func (c *SendPostContent) SendContentMsgType() string {
+	return "post"
+}
+
+// Validate 校验富文本内容: 至少包含一种支持的语言, 每种语言有标题或内容, 段落不能为空,
+// 元素必须填写对应的字段, 图片需要单独成段
+func (c *SendPostContent) Validate() error {
+	if len(c.Post) == 0 {
+		return fmt.Errorf("Empty post content")
+	}
+
+	locales := make([]string, 0, len(c.Post))
+	for locale := range c.Post {
+		locales = append(locales, locale)
+	}
+	sort.Strings(locales)
+
+	for _, locale := range locales {
+		if !isPostLocale(locale) {
+			return fmt.Errorf("Unsupported post locale %q", locale)
+		}
+		lc := c.Post[locale]
+		if lc == nil || (lc.Title == "" && len(lc.Content) == 0) {
+			return fmt.Errorf("Empty post content for locale %q", locale)
+		}
+		for i, paragraph := range lc.Content {
+			if len(paragraph) == 0 {
+				return fmt.Errorf("Empty paragraph %d for locale %q", i, locale)
+			}
+			for _, elem := range paragraph {
+				if err := elem.validate(); err != nil {
+					return fmt.Errorf("Paragraph %d for locale %q: %s", i, locale, err)
+				}
+				if elem.Tag == "img" && len(paragraph) != 1 {
+					return fmt.Errorf("Paragraph %d for locale %q: img must be the only element in its paragraph", i, locale)
+				}
+			}
+		}
+	}
+	return nil
+}
+
+func (elem *PostElement) validate() error {
+	switch elem.Tag {
+	case "text":
+		if elem.Text == "" {
+			return fmt.Errorf("text element missing text")
+		}
+	case "a":
+		if elem.Text == "" || elem.Href == "" {
+			return fmt.Errorf("a element missing text or href")
+		}
+	case "at":
+		if elem.UserId == "" {
+			return fmt.Errorf("at element missing user_id")
+		}
+	case "img":
+		if elem.ImageKey == "" {
+			return fmt.Errorf("img element missing image_key")
+		}
+	default:
+		return fmt.Errorf("Unsupported post element tag %q", elem.Tag)
+	}
+	return nil
+}
+
+func isPostLocale(locale string) bool {
+	for _, l := range PostLocales {
+		if l == locale {
+			return true
+		}
+	}
+	return false
+}
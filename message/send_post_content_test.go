@@ -0,0 +1,96 @@
+package message
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/huangjunwen/feishu-driver/conf"
+	"github.com/huangjunwen/feishu-driver/utils"
+)
+
+func TestPostBuilder(t *testing.T) {
+	assert := assert.New(t)
+
+	content, err := NewPostBuilder().
+		Title("发布通知").
+		Text("服务 svc 已发布到 ").Link("prod", "https://example.com").
+		Paragraph().At("ou_1").Text(" 请关注").AtAll().
+		Paragraph().Image("img_1", 300, 200).
+		Locale("en_us").Title("Release").
+		Text("svc is released").
+		Build()
+	assert.NoError(err)
+
+	b, err := json.Marshal(content)
+	assert.NoError(err)
+	assert.JSONEq(`{
+		"post": {
+			"zh_cn": {
+				"title": "发布通知",
+				"content": [
+					[
+						{"tag": "text", "text": "服务 svc 已发布到 "},
+						{"tag": "a", "text": "prod", "href": "https://example.com"}
+					],
+					[
+						{"tag": "at", "user_id": "ou_1"},
+						{"tag": "text", "text": " 请关注"},
+						{"tag": "at", "user_id": "all"}
+					],
+					[
+						{"tag": "img", "image_key": "img_1", "width": 300, "height": 200}
+					]
+				]
+			},
+			"en_us": {
+				"title": "Release",
+				"content": [[{"tag": "text", "text": "svc is released"}]]
+			}
+		}
+	}`, string(b))
+
+	// 校验
+	for _, builder := range []*PostBuilder{
+		NewPostBuilder(),
+		NewPostBuilder().Locale("fr_fr").Text("bonjour"),
+		NewPostBuilder().Locale("en_us"),
+		NewPostBuilder().Text("a").Paragraph().Paragraph().Text("b"),
+		NewPostBuilder().Text("a").Image("img_1", 1, 1),
+		NewPostBuilder().Link("a", ""),
+		NewPostBuilder().At(""),
+		NewPostBuilder().Element(&PostElement{Tag: "xxx"}),
+	} {
+		_, err := builder.Build()
+		assert.Error(err)
+	}
+}
+
+func TestSendPost(t *testing.T) {
+	assert := assert.New(t)
+
+	srv, reqs := newIMTestServer(func(r *http.Request) string {
+		return `{"code": 0, "msg": "ok", "data": {"message_id": "om_1"}}`
+	})
+	defer srv.Close()
+	ctx := utils.APIOptions{URLBase: srv.URL}.WithCtx(context.Background())
+	provider := conf.TenantAccessTokenProviderFunc(func() (string, error) { return "t-token", nil })
+
+	content, _ := NewPostBuilder().Title("t").Text("hi").Build()
+	res, err := Send{ChatId: "oc_1", Content: content}.Do(ctx, provider)
+	assert.NoError(err)
+	assert.Equal("om_1", res.Data.MessageId)
+	assert.JSONEq(`{
+		"chat_id": "oc_1",
+		"msg_type": "post",
+		"content": {"post": {"zh_cn": {"title": "t", "content": [[{"tag": "text", "text": "hi"}]]}}}
+	}`, (*reqs)[0].Body)
+
+	// 发送前校验
+	_, err = Send{ChatId: "oc_1", Content: &SendPostContent{}}.Do(ctx, provider)
+	assert.Error(err)
+	assert.Len(*reqs, 1)
+}
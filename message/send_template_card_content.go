@@ -0,0 +1,51 @@
+package message
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// SendTemplateCardContent 代表使用卡片模板 (在卡片搭建工具中发布) 发送的消息卡片, 发送时放在请求的 card 字段中
+type SendTemplateCardContent struct {
+	// TemplateId 是卡片模板 id, 必须填写
+	TemplateId string
+
+	// TemplateVersionName 是卡片模板版本, 为空时使用最新版本
+	TemplateVersionName string
+
+	// Variables 是模板变量
+	Variables map[string]interface{}
+}
+
+// SendContentMsgType 返回 interactive
+func (c *SendTemplateCardContent) SendContentMsgType() string {
+	return "interactive"
+}
+
+// Validate 校验
+func (c *SendTemplateCardContent) Validate() error {
+	if c.TemplateId == "" {
+		return fmt.Errorf("Missing template id")
+	}
+	return nil
+}
+
+// MarshalJSON 编码为 {"type": "template", "data": {...}}
+func (c *SendTemplateCardContent) MarshalJSON() ([]byte, error) {
+	type data struct {
+		TemplateId          string                 `json:"template_id"`
+		TemplateVersionName string                 `json:"template_version_name,omitempty"`
+		TemplateVariable    map[string]interface{} `json:"template_variable,omitempty"`
+	}
+	return json.Marshal(&struct {
+		Type string `json:"type"`
+		Data data   `json:"data"`
+	}{
+		Type: "template",
+		Data: data{
+			TemplateId:          c.TemplateId,
+			TemplateVersionName: c.TemplateVersionName,
+			TemplateVariable:    c.Variables,
+		},
+	})
+}
@@ -0,0 +1,61 @@
+package message
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/huangjunwen/feishu-driver/conf"
+	"github.com/huangjunwen/feishu-driver/message/card"
+	"github.com/huangjunwen/feishu-driver/utils"
+)
+
+func TestSendTemplateCard(t *testing.T) {
+	assert := assert.New(t)
+
+	srv, reqs := newIMTestServer(func(r *http.Request) string {
+		return `{"code": 0, "msg": "ok", "data": {"message_id": "om_1"}}`
+	})
+	defer srv.Close()
+	ctx := utils.APIOptions{URLBase: srv.URL}.WithCtx(context.Background())
+	provider := conf.TenantAccessTokenProviderFunc(func() (string, error) { return "t-token", nil })
+	last := func() recordedRequest { return (*reqs)[len(*reqs)-1] }
+
+	_, err := Send{ChatId: "oc_1", Content: &SendTemplateCardContent{
+		TemplateId:          "ctp_1",
+		TemplateVersionName: "1.0.2",
+		Variables:           map[string]interface{}{"svc": "order", "items": []string{"a", "b"}},
+	}}.Do(ctx, provider)
+	assert.NoError(err)
+	assert.JSONEq(`{
+		"chat_id": "oc_1",
+		"msg_type": "interactive",
+		"card": {
+			"type": "template",
+			"data": {
+				"template_id": "ctp_1",
+				"template_version_name": "1.0.2",
+				"template_variable": {"svc": "order", "items": ["a", "b"]}
+			}
+		}
+	}`, last().Body)
+
+	_, err = Send{ChatId: "oc_1", Content: &SendTemplateCardContent{}}.Do(ctx, provider)
+	assert.Error(err)
+
+	// 本地渲染
+	raw, err := card.RenderTemplate([]byte(`{"elements": [{"tag": "markdown", "content": "${svc}"}]}`), map[string]interface{}{"svc": "order"})
+	assert.NoError(err)
+	_, err = Send{ChatId: "oc_1", Content: &SendCardContent{Raw: raw}}.Do(ctx, provider)
+	assert.NoError(err)
+	assert.JSONEq(`{
+		"chat_id": "oc_1",
+		"msg_type": "interactive",
+		"card": {"elements": [{"tag": "markdown", "content": "order"}]}
+	}`, last().Body)
+
+	_, err = Send{ChatId: "oc_1", Content: &SendCardContent{Raw: raw, Card: card.New()}}.Do(ctx, provider)
+	assert.Error(err)
+}
@@ -0,0 +1,83 @@
+package message
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+
+	"github.com/huangjunwen/feishu-driver/conf"
+	"github.com/huangjunwen/feishu-driver/utils"
+)
+
+var (
+	// UrgentTypeApp 是应用内加急
+	UrgentTypeApp = "app"
+
+	// UrgentTypeSMS 是短信加急
+	UrgentTypeSMS = "sms"
+
+	// UrgentTypePhone 是电话加急
+	UrgentTypePhone = "phone"
+)
+
+// Urgent 对已发送的消息进行加急 (im/v1 urgent_app/urgent_sms/urgent_phone)
+type Urgent struct {
+	// MessageId 是已发送的消息
+	MessageId string
+
+	// Type 是加急类型: UrgentTypeApp/UrgentTypeSMS/UrgentTypePhone
+	Type string
+
+	// UserIdType 是 UserIds 的类型: open_id/user_id/union_id, 为空时使用 open_id
+	UserIdType string
+
+	// UserIds 是需要加急的用户, 必须是消息所在会话的成员
+	UserIds []string
+}
+
+// UrgentResult 是加急接口的结果
+type UrgentResult struct {
+	utils.APIResultBase
+
+	Data struct {
+		// InvalidUserIdList 是无效的用户
+		InvalidUserIdList []string `json:"invalid_user_id_list"`
+	} `json:"data"`
+}
+
+// Do 调用 api
+func (req Urgent) Do(ctx context.Context, provider conf.TenantAccessTokenProvider) (*UrgentResult, error) {
+	switch req.Type {
+	case UrgentTypeApp, UrgentTypeSMS, UrgentTypePhone:
+	default:
+		return nil, fmt.Errorf("Unsupported urgent type %q", req.Type)
+	}
+	if req.MessageId == "" {
+		return nil, fmt.Errorf("Missing message id")
+	}
+	if len(req.UserIds) == 0 {
+		return nil, fmt.Errorf("Missing user ids")
+	}
+	userIdType := req.UserIdType
+	if userIdType == "" {
+		userIdType = ReceiveIdTypeOpenId
+	}
+
+	body := &struct {
+		UserIdList []string `json:"user_id_list"`
+	}{
+		UserIdList: req.UserIds,
+	}
+	result := &UrgentResult{}
+	err := utils.PatchJSONWithTenantAccessToken(
+		ctx,
+		fmt.Sprintf("/im/v1/messages/%s/urgent_%s?user_id_type=%s", url.PathEscape(req.MessageId), req.Type, url.QueryEscape(userIdType)),
+		provider,
+		body,
+		result,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
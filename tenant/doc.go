@@ -0,0 +1,3 @@
+// Package tenant 根据应用商店应用的生命周期事件 (开通/停启用/卸载/购买) 维护租户信息，
+// 并为每个租户自动创建/销毁 tenant access token provider, 主要功能由 Manager 提供
+package tenant
@@ -0,0 +1,276 @@
+package tenant
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/huangjunwen/golibs/logr"
+
+	"github.com/huangjunwen/feishu-driver/app"
+	"github.com/huangjunwen/feishu-driver/conf"
+	"github.com/huangjunwen/feishu-driver/webhook"
+	"github.com/huangjunwen/feishu-driver/webhook/events"
+)
+
+// TokenProvider 是租户的 tenant access token provider, 租户停用/卸载时会调用 Stop,
+// *app.PublicAppTenant 满足该接口
+type TokenProvider interface {
+	conf.TenantAccessTokenProvider
+
+	// Stop 停止更新
+	Stop()
+}
+
+// Hook 是租户生命周期回调, 返回错误时事件处理失败 (webhook 会返回 500 让飞书重试)
+type Hook func(ctx context.Context, tenant *Tenant) error
+
+// PaidHook 是租户购买回调
+type PaidHook func(ctx context.Context, tenant *Tenant, order *events.OrderPaid) error
+
+// Manager 订阅应用商店应用的生命周期事件 (AppOpen/AppStatusChange/AppUninstalled/OrderPaid),
+// 将租户信息维护在 Store 中，调用对应的回调，并为已开通且启用的租户维护 TokenProvider.
+//
+// 所有事件串行处理; 回调中可以调用 TenantAccessTokenProvider/Tenant 等方法, 但不能调用 HandleEvent
+type Manager struct {
+	appAccessTokenProvider conf.AppAccessTokenProvider
+	appId                  string
+	store                  Store
+	tenantOpts             []app.PublicAppTenantOption
+	newTokenProvider       func(tenantKey string) (TokenProvider, error)
+	logger                 logr.Logger
+
+	onInstall   Hook
+	onUninstall Hook
+	onEnable    Hook
+	onDisable   Hook
+	onPaid      PaidHook
+
+	eventMu sync.Mutex // 串行处理事件
+
+	mu        sync.Mutex // 保护 providers
+	providers map[string]TokenProvider
+}
+
+// NewManager 创建 Manager, 其中 appAccessTokenProvider 必须是应用商店应用的 app access token provider;
+// 创建时会为 Store 中已开通且启用的租户创建 TokenProvider
+func NewManager(ctx context.Context, appAccessTokenProvider conf.AppAccessTokenProvider, opts ...ManagerOption) (*Manager, error) {
+	m := &Manager{
+		appAccessTokenProvider: appAccessTokenProvider,
+		store:                  NewMemoryStore(),
+		logger:                 logr.Nop,
+		onInstall:              nopHook,
+		onUninstall:            nopHook,
+		onEnable:               nopHook,
+		onDisable:              nopHook,
+		onPaid:                 func(context.Context, *Tenant, *events.OrderPaid) error { return nil },
+		providers:              map[string]TokenProvider{},
+	}
+	m.newTokenProvider = m.newPublicAppTenant
+	for _, opt := range opts {
+		if err := opt(m); err != nil {
+			return nil, err
+		}
+	}
+
+	tenants, err := m.store.List(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for _, tenant := range tenants {
+		if tenant.Installed && tenant.Active {
+			if err := m.startProvider(tenant.TenantKey); err != nil {
+				m.Stop()
+				return nil, err
+			}
+		}
+	}
+	return m, nil
+}
+
+func nopHook(context.Context, *Tenant) error { return nil }
+
+func (m *Manager) newPublicAppTenant(tenantKey string) (TokenProvider, error) {
+	return app.NewPublicAppTenant(m.appAccessTokenProvider, tenantKey, m.tenantOpts...)
+}
+
+// TenantAccessTokenProvider 返回租户的 tenant access token provider, 租户未开通或已停用时返回 nil
+func (m *Manager) TenantAccessTokenProvider(tenantKey string) conf.TenantAccessTokenProvider {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	provider, ok := m.providers[tenantKey]
+	if !ok {
+		return nil
+	}
+	return provider
+}
+
+// Tenant 返回租户信息, 不存在时返回 nil, nil
+func (m *Manager) Tenant(ctx context.Context, tenantKey string) (*Tenant, error) {
+	return m.store.Get(ctx, tenantKey)
+}
+
+// Stop 停止所有 TokenProvider
+func (m *Manager) Stop() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for tenantKey, provider := range m.providers {
+		provider.Stop()
+		delete(m.providers, tenantKey)
+	}
+}
+
+// HandleEvent 处理生命周期事件, 其它事件 (或其它应用的事件) 直接忽略并返回 handled=false
+func (m *Manager) HandleEvent(ctx context.Context, ev interface{}) (handled bool, err error) {
+	var (
+		appId     string
+		tenantKey string
+	)
+	switch e := ev.(type) {
+	case *events.AppOpen:
+		appId, tenantKey = e.AppId, e.TenantKey
+	case *events.AppStatusChange:
+		appId, tenantKey = e.AppId, e.TenantKey
+	case *events.AppUninstalled:
+		appId, tenantKey = e.AppId, e.TenantKey
+	case *events.OrderPaid:
+		appId, tenantKey = e.AppId, e.TenantKey
+	default:
+		return false, nil
+	}
+	if m.appId != "" && appId != m.appId {
+		return false, nil
+	}
+
+	m.eventMu.Lock()
+	defer m.eventMu.Unlock()
+
+	tenant, err := m.store.Get(ctx, tenantKey)
+	if err != nil {
+		return true, err
+	}
+	if tenant == nil {
+		tenant = &Tenant{TenantKey: tenantKey}
+	}
+	now := time.Now()
+	tenant.UpdatedAt = now
+
+	var (
+		running bool
+		hook    func() error
+	)
+	switch e := ev.(type) {
+	case *events.AppOpen:
+		tenant.Installed = true
+		tenant.Active = true
+		tenant.InstallerOpenId = e.Installer.OpenId
+		tenant.InstallerUserId = e.Installer.UserId
+		tenant.InstalledAt = now
+		running = true
+		hook = func() error { return m.onInstall(ctx, tenant) }
+
+	case *events.AppStatusChange:
+		// status: start_by_tenant/stop_by_tenant/stop_by_platform
+		if strings.HasPrefix(e.Status, "start") {
+			tenant.Installed = true
+			tenant.Active = true
+			running = true
+			hook = func() error { return m.onEnable(ctx, tenant) }
+		} else {
+			tenant.Active = false
+			hook = func() error { return m.onDisable(ctx, tenant) }
+		}
+
+	case *events.AppUninstalled:
+		tenant.Installed = false
+		tenant.Active = false
+		hook = func() error { return m.onUninstall(ctx, tenant) }
+
+	case *events.OrderPaid:
+		tenant.PricePlanId = e.PricePlanId
+		tenant.PricePlanType = e.PricePlanType
+		tenant.Seats = e.Seats
+		tenant.LastOrderId = e.OrderId
+		running = tenant.Installed && tenant.Active
+		hook = func() error { return m.onPaid(ctx, tenant, e) }
+	}
+
+	return true, m.apply(ctx, tenant, running, hook)
+}
+
+// apply 保存租户信息, 启动/停止 TokenProvider, 然后调用回调; 需要在持有 eventMu 时调用
+func (m *Manager) apply(ctx context.Context, tenant *Tenant, running bool, hook func() error) error {
+	if err := m.store.Put(ctx, tenant); err != nil {
+		return err
+	}
+
+	if running {
+		if err := m.startProvider(tenant.TenantKey); err != nil {
+			return err
+		}
+	} else {
+		m.stopProvider(tenant.TenantKey)
+	}
+
+	if err := hook(); err != nil {
+		m.logger.Error(err, "Tenant hook error", "tenantKey", tenant.TenantKey)
+		return err
+	}
+	return nil
+}
+
+func (m *Manager) startProvider(tenantKey string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if _, ok := m.providers[tenantKey]; ok {
+		return nil
+	}
+	provider, err := m.newTokenProvider(tenantKey)
+	if err != nil {
+		return err
+	}
+	m.providers[tenantKey] = provider
+	return nil
+}
+
+func (m *Manager) stopProvider(tenantKey string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	provider, ok := m.providers[tenantKey]
+	if !ok {
+		return
+	}
+	provider.Stop()
+	delete(m.providers, tenantKey)
+}
+
+// Wrap 返回一个 webhook.PayloadHandler: 生命周期事件由 Manager 处理 (出错时返回 500)，
+// 然后交给 next 处理 (next 为 nil 时直接返回 ok)
+func (m *Manager) Wrap(next webhook.PayloadHandler) webhook.PayloadHandler {
+	return func(w http.ResponseWriter, r *http.Request, payload *webhook.Payload) {
+		if _, err := m.HandleEvent(r.Context(), payload.GetEvent()); err != nil {
+			http.Error(w, "Handle tenant lifecycle event error", 500)
+			return
+		}
+		if next == nil {
+			w.Write([]byte("ok"))
+			return
+		}
+		next(w, r, payload)
+	}
+}
+
+// WrapFunc 类似于 Wrap, 用于 webhook.PayloadFunc (长连接/重放等场景), next 可以为 nil
+func (m *Manager) WrapFunc(next webhook.PayloadFunc) webhook.PayloadFunc {
+	return func(ctx context.Context, payload *webhook.Payload) error {
+		if _, err := m.HandleEvent(ctx, payload.GetEvent()); err != nil {
+			return err
+		}
+		if next == nil {
+			return nil
+		}
+		return next(ctx, payload)
+	}
+}
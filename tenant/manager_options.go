@@ -0,0 +1,113 @@
+package tenant
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/huangjunwen/golibs/logr"
+
+	"github.com/huangjunwen/feishu-driver/app"
+	"github.com/huangjunwen/feishu-driver/webhook/events"
+)
+
+// ManagerOption 是创建 Manager 的选项
+type ManagerOption func(*Manager) error
+
+// TMAppId 设置应用 app_id, 设置后只处理该应用的事件 (多个应用共用一个 webhook 入口时需要设置)
+func TMAppId(appId string) ManagerOption {
+	return func(m *Manager) error {
+		m.appId = appId
+		return nil
+	}
+}
+
+// TMStore 设置租户信息的存储, 默认为 MemoryStore
+func TMStore(store Store) ManagerOption {
+	return func(m *Manager) error {
+		if store == nil {
+			return fmt.Errorf("TMStore: nil store")
+		}
+		m.store = store
+		return nil
+	}
+}
+
+// TMTenantOptions 设置创建 PublicAppTenant 时的选项
+func TMTenantOptions(opts ...app.PublicAppTenantOption) ManagerOption {
+	return func(m *Manager) error {
+		m.tenantOpts = opts
+		return nil
+	}
+}
+
+// TMTokenProviderFactory 设置创建租户 TokenProvider 的函数, 默认使用 app.NewPublicAppTenant
+func TMTokenProviderFactory(fn func(tenantKey string) (TokenProvider, error)) ManagerOption {
+	return func(m *Manager) error {
+		if fn == nil {
+			return fmt.Errorf("TMTokenProviderFactory: nil factory")
+		}
+		m.newTokenProvider = fn
+		return nil
+	}
+}
+
+// TMOnInstall 在租户开通应用 (AppOpen) 后回调
+func TMOnInstall(fn Hook) ManagerOption {
+	return func(m *Manager) error {
+		m.onInstall = hookOrNop(fn)
+		return nil
+	}
+}
+
+// TMOnUninstall 在租户卸载应用 (AppUninstalled) 后回调
+func TMOnUninstall(fn Hook) ManagerOption {
+	return func(m *Manager) error {
+		m.onUninstall = hookOrNop(fn)
+		return nil
+	}
+}
+
+// TMOnEnable 在租户启用应用 (AppStatusChange) 后回调
+func TMOnEnable(fn Hook) ManagerOption {
+	return func(m *Manager) error {
+		m.onEnable = hookOrNop(fn)
+		return nil
+	}
+}
+
+// TMOnDisable 在租户停用应用 (AppStatusChange) 后回调
+func TMOnDisable(fn Hook) ManagerOption {
+	return func(m *Manager) error {
+		m.onDisable = hookOrNop(fn)
+		return nil
+	}
+}
+
+// TMOnPaid 在租户购买应用 (OrderPaid) 后回调
+func TMOnPaid(fn PaidHook) ManagerOption {
+	return func(m *Manager) error {
+		if fn == nil {
+			fn = func(context.Context, *Tenant, *events.OrderPaid) error { return nil }
+		}
+		m.onPaid = fn
+		return nil
+	}
+}
+
+// TMLogger 设置日志
+func TMLogger(logger logr.Logger) ManagerOption {
+	return func(m *Manager) error {
+		if logger == nil {
+			logger = logr.Nop
+		}
+		m.logger = logger
+		return nil
+	}
+}
+
+func hookOrNop(fn Hook) Hook {
+	if fn == nil {
+		return nopHook
+	}
+	return fn
+}
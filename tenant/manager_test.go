@@ -0,0 +1,229 @@
+package tenant
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/huangjunwen/feishu-driver/conf"
+	"github.com/huangjunwen/feishu-driver/webhook"
+	"github.com/huangjunwen/feishu-driver/webhook/events"
+)
+
+type fakeProvider struct {
+	tenantKey string
+	stopped   bool
+}
+
+func (p *fakeProvider) FeishuTenantAccessToken() (string, error) { return "t-" + p.tenantKey, nil }
+func (p *fakeProvider) Stop()                                    { p.stopped = true }
+
+func TestManager(t *testing.T) {
+	assert := assert.New(t)
+	ctx := context.Background()
+
+	store := NewMemoryStore()
+	store.Put(ctx, &Tenant{TenantKey: "t0", Installed: true, Active: true})
+	store.Put(ctx, &Tenant{TenantKey: "t1", Installed: true, Active: false})
+
+	var (
+		providers = map[string]*fakeProvider{}
+		calls     = []string{}
+		hookErr   error
+	)
+	hook := func(name string) Hook {
+		return func(ctx context.Context, tenant *Tenant) error {
+			calls = append(calls, fmt.Sprintf("%s:%s", name, tenant.TenantKey))
+			return hookErr
+		}
+	}
+	m, err := NewManager(ctx, nil,
+		TMAppId("cli_a"),
+		TMStore(store),
+		TMTokenProviderFactory(func(tenantKey string) (TokenProvider, error) {
+			p := &fakeProvider{tenantKey: tenantKey}
+			providers[tenantKey] = p
+			return p, nil
+		}),
+		TMOnInstall(hook("install")),
+		TMOnUninstall(hook("uninstall")),
+		TMOnEnable(hook("enable")),
+		TMOnDisable(hook("disable")),
+		TMOnPaid(func(ctx context.Context, tenant *Tenant, order *events.OrderPaid) error {
+			calls = append(calls, fmt.Sprintf("paid:%s:%s", tenant.TenantKey, order.OrderId))
+			return nil
+		}),
+	)
+	assert.NoError(err)
+
+	runningTenants := func() []string {
+		ret := []string{}
+		for tenantKey := range providers {
+			if m.TenantAccessTokenProvider(tenantKey) != nil {
+				ret = append(ret, tenantKey)
+			}
+		}
+		sort.Strings(ret)
+		return ret
+	}
+
+	// 从 store 中恢复
+	assert.Equal([]string{"t0"}, runningTenants())
+
+	handle := func(ev interface{}) {
+		handled, err := m.HandleEvent(ctx, ev)
+		assert.True(handled)
+		assert.NoError(err)
+	}
+
+	// 开通
+	open := &events.AppOpen{AppId: "cli_a", TenantKey: "t2"}
+	open.Installer.OpenId = "ou_1"
+	handle(open)
+	assert.Equal([]string{"t0", "t2"}, runningTenants())
+	{
+		tenant, err := m.Tenant(ctx, "t2")
+		assert.NoError(err)
+		assert.True(tenant.Installed)
+		assert.True(tenant.Active)
+		assert.Equal("ou_1", tenant.InstallerOpenId)
+		token, _ := m.TenantAccessTokenProvider("t2").FeishuTenantAccessToken()
+		assert.Equal("t-t2", token)
+	}
+
+	// 购买
+	handle(&events.OrderPaid{AppId: "cli_a", TenantKey: "t2", OrderId: "o1", PricePlanId: "p1", Seats: 20})
+	{
+		tenant, _ := m.Tenant(ctx, "t2")
+		assert.Equal("p1", tenant.PricePlanId)
+		assert.Equal(20, tenant.Seats)
+		assert.Equal("o1", tenant.LastOrderId)
+	}
+
+	// 停用/启用
+	handle(&events.AppStatusChange{AppId: "cli_a", TenantKey: "t0", Status: "stop_by_tenant"})
+	assert.True(providers["t0"].stopped)
+	handle(&events.AppStatusChange{AppId: "cli_a", TenantKey: "t1", Status: "start_by_tenant"})
+	assert.Equal([]string{"t1", "t2"}, runningTenants())
+
+	// 卸载
+	handle(&events.AppUninstalled{AppId: "cli_a", TenantKey: "t2"})
+	assert.True(providers["t2"].stopped)
+	assert.Nil(m.TenantAccessTokenProvider("t2"))
+	{
+		tenant, _ := m.Tenant(ctx, "t2")
+		assert.False(tenant.Installed)
+		assert.Equal("p1", tenant.PricePlanId)
+	}
+
+	assert.Equal([]string{
+		"install:t2",
+		"paid:t2:o1",
+		"disable:t0",
+		"enable:t1",
+		"uninstall:t2",
+	}, calls)
+
+	// 其它应用/其它事件被忽略
+	{
+		handled, err := m.HandleEvent(ctx, &events.AppOpen{AppId: "cli_b", TenantKey: "t3"})
+		assert.False(handled)
+		assert.NoError(err)
+		handled, err = m.HandleEvent(ctx, &events.UserAdd{})
+		assert.False(handled)
+		assert.NoError(err)
+		assert.Nil(m.TenantAccessTokenProvider("t3"))
+	}
+
+	// 通过 webhook 接收
+	{
+		verifToken := "GzhQEyfUcx7eEungQFWtXgCbxSpUOJIb"
+		next := 0
+		h := webhook.New(conf.NewWebhookConfig(verifToken, ""), m.Wrap(func(w http.ResponseWriter, r *http.Request, payload *webhook.Payload) {
+			next++
+		}))
+		post := func() int {
+			w := httptest.NewRecorder()
+			h.ServeHTTP(w, httptest.NewRequest("POST", "/", bytes.NewBufferString(fmt.Sprintf(`{
+				"uuid": "1", "token": "%s", "ts": "1", "type": "event_callback",
+				"event": {"type": "app_open", "app_id": "cli_a", "tenant_key": "t4", "installer": {"open_id": "ou_2"}}
+			}`, verifToken))))
+			return w.Code
+		}
+		assert.Equal(200, post())
+		assert.Equal(1, next)
+		assert.NotNil(m.TenantAccessTokenProvider("t4"))
+
+		// 回调错误时返回 500 让飞书重试
+		hookErr = fmt.Errorf("hook error")
+		assert.Equal(500, post())
+		assert.Equal(1, next)
+	}
+
+	m.Stop()
+	for _, p := range providers {
+		assert.True(p.stopped)
+	}
+}
+
+func TestManagerHookReentrant(t *testing.T) {
+	assert := assert.New(t)
+	ctx := context.Background()
+
+	var m *Manager
+	tokens := []string{}
+	m, err := NewManager(ctx, nil,
+		TMTokenProviderFactory(func(tenantKey string) (TokenProvider, error) {
+			return &fakeProvider{tenantKey: tenantKey}, nil
+		}),
+		// 回调中获取新租户的 provider 及租户信息 (例如发送欢迎消息)
+		TMOnInstall(func(ctx context.Context, tenant *Tenant) error {
+			provider := m.TenantAccessTokenProvider(tenant.TenantKey)
+			if provider == nil {
+				return fmt.Errorf("no provider")
+			}
+			token, err := provider.FeishuTenantAccessToken()
+			if err != nil {
+				return err
+			}
+			tokens = append(tokens, token)
+			_, err = m.Tenant(ctx, tenant.TenantKey)
+			return err
+		}),
+	)
+	assert.NoError(err)
+
+	done := make(chan error, 1)
+	go func() {
+		_, err := m.HandleEvent(ctx, &events.AppOpen{TenantKey: "t1"})
+		done <- err
+	}()
+	select {
+	case err := <-done:
+		assert.NoError(err)
+	case <-time.After(5 * time.Second):
+		t.Fatal("HandleEvent deadlocked")
+	}
+	assert.Equal([]string{"t-t1"}, tokens)
+}
+
+func TestTenantJSON(t *testing.T) {
+	assert := assert.New(t)
+
+	b, err := json.Marshal(&Tenant{TenantKey: "t1", InstallerOpenId: "ou_1", PricePlanId: "p1", LastOrderId: "o1"})
+	assert.NoError(err)
+	m := map[string]interface{}{}
+	assert.NoError(json.Unmarshal(b, &m))
+	for _, key := range []string{"tenant_key", "installer_open_id", "installer_user_id", "price_plan_id", "price_plan_type", "last_order_id", "installed_at", "updated_at"} {
+		assert.Contains(m, key)
+	}
+	assert.Equal("t1", m["tenant_key"])
+}
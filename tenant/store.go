@@ -0,0 +1,97 @@
+package tenant
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+)
+
+var (
+	_ Store = (*MemoryStore)(nil)
+)
+
+// Tenant 是租户 (开通了应用的企业) 的信息
+type Tenant struct {
+	// TenantKey 是企业唯一标识
+	TenantKey string `json:"tenant_key"`
+
+	// Installed 表示应用已开通 (未卸载)
+	Installed bool `json:"installed"`
+
+	// Active 表示应用处于启用状态
+	Active bool `json:"active"`
+
+	// InstallerOpenId/InstallerUserId 是开通应用的用户
+	InstallerOpenId string `json:"installer_open_id"`
+	InstallerUserId string `json:"installer_user_id"`
+
+	// PricePlanId/PricePlanType/Seats/LastOrderId 来自最近一次购买
+	PricePlanId   string `json:"price_plan_id"`
+	PricePlanType string `json:"price_plan_type"`
+	Seats         int    `json:"seats"`
+	LastOrderId   string `json:"last_order_id"`
+
+	// InstalledAt 是最近一次开通的时间
+	InstalledAt time.Time `json:"installed_at"`
+
+	// UpdatedAt 是最近一次更新的时间
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// Store 用于持久化租户信息, 需要可并发使用
+type Store interface {
+	// Get 返回租户信息, 不存在时返回 nil, nil
+	Get(ctx context.Context, tenantKey string) (*Tenant, error)
+
+	// Put 保存租户信息
+	Put(ctx context.Context, tenant *Tenant) error
+
+	// List 返回所有租户信息
+	List(ctx context.Context) ([]*Tenant, error)
+}
+
+// MemoryStore 是保存在内存中的 Store, 一般用于测试
+type MemoryStore struct {
+	mu      sync.Mutex
+	tenants map[string]Tenant
+}
+
+// NewMemoryStore 创建一个 MemoryStore
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{
+		tenants: map[string]Tenant{},
+	}
+}
+
+// Get 满足 Store 接口
+func (store *MemoryStore) Get(ctx context.Context, tenantKey string) (*Tenant, error) {
+	store.mu.Lock()
+	defer store.mu.Unlock()
+	tenant, ok := store.tenants[tenantKey]
+	if !ok {
+		return nil, nil
+	}
+	return &tenant, nil
+}
+
+// Put 满足 Store 接口
+func (store *MemoryStore) Put(ctx context.Context, tenant *Tenant) error {
+	store.mu.Lock()
+	defer store.mu.Unlock()
+	store.tenants[tenant.TenantKey] = *tenant
+	return nil
+}
+
+// List 满足 Store 接口, 按 TenantKey 排序
+func (store *MemoryStore) List(ctx context.Context) ([]*Tenant, error) {
+	store.mu.Lock()
+	defer store.mu.Unlock()
+	ret := make([]*Tenant, 0, len(store.tenants))
+	for _, tenant := range store.tenants {
+		tenant := tenant
+		ret = append(ret, &tenant)
+	}
+	sort.Slice(ret, func(i, j int) bool { return ret[i].TenantKey < ret[j].TenantKey })
+	return ret, nil
+}
@@ -5,6 +5,7 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"io"
 	"net/http"
 	"net/url"
 
@@ -71,6 +72,32 @@ func PostJSONWithTenantAccessToken(ctx context.Context, urlPath string, provider
 	}, body, result)
 }
 
+// PutJSONWithTenantAccessToken 类似于 PostJSONWithTenantAccessToken，不过使用 PUT 方法
+func PutJSONWithTenantAccessToken(ctx context.Context, urlPath string, provider conf.TenantAccessTokenProvider, body interface{}, result interface{}) error {
+	return doJSONWithTenantAccessToken(ctx, "PUT", urlPath, provider, body, result)
+}
+
+// PatchJSONWithTenantAccessToken 类似于 PostJSONWithTenantAccessToken，不过使用 PATCH 方法
+func PatchJSONWithTenantAccessToken(ctx context.Context, urlPath string, provider conf.TenantAccessTokenProvider, body interface{}, result interface{}) error {
+	return doJSONWithTenantAccessToken(ctx, "PATCH", urlPath, provider, body, result)
+}
+
+// DeleteJSONWithTenantAccessToken 使用 DELETE 方法调用接口，body 为 nil 时请求没有 body，其它类似于 PostJSONWithTenantAccessToken
+func DeleteJSONWithTenantAccessToken(ctx context.Context, urlPath string, provider conf.TenantAccessTokenProvider, body interface{}, result interface{}) error {
+	return doJSONWithTenantAccessToken(ctx, "DELETE", urlPath, provider, body, result)
+}
+
+func doJSONWithTenantAccessToken(ctx context.Context, method, urlPath string, provider conf.TenantAccessTokenProvider, body interface{}, result interface{}) error {
+	token, err := provider.FeishuTenantAccessToken()
+	if err != nil {
+		return err
+	}
+	return doJSON(ctx, method, urlPath, func(req *http.Request) *http.Request {
+		req.Header.Add("Authorization", "Bearer "+token)
+		return req
+	}, body, result)
+}
+
 func getJSON(ctx context.Context, urlPath string, reqModify func(*http.Request) *http.Request, params url.Values, result interface{}) error {
 	opts := CtxAPIOptions(ctx)
 
@@ -91,6 +118,7 @@ func getJSON(ctx context.Context, urlPath string, reqModify func(*http.Request)
 	if err != nil {
 		return err
 	}
+	defer resp.Body.Close()
 
 	if err := json.NewDecoder(resp.Body).Decode(result); err != nil {
 		return err
@@ -100,23 +128,34 @@ func getJSON(ctx context.Context, urlPath string, reqModify func(*http.Request)
 }
 
 func postJSON(ctx context.Context, urlPath string, reqModify func(*http.Request) *http.Request, body interface{}, result interface{}) error {
+	return doJSON(ctx, "POST", urlPath, reqModify, body, result)
+}
+
+// doJSON 使用 method 方法调用接口，body 非 nil 时用 json 编码作为请求的 body
+func doJSON(ctx context.Context, method, urlPath string, reqModify func(*http.Request) *http.Request, body interface{}, result interface{}) error {
 	opts := CtxAPIOptions(ctx)
 
-	buf := &bytes.Buffer{}
-	if err := json.NewEncoder(buf).Encode(body); err != nil {
-		return err
+	var reqBody io.Reader
+	if body != nil {
+		buf := &bytes.Buffer{}
+		if err := json.NewEncoder(buf).Encode(body); err != nil {
+			return err
+		}
+		reqBody = buf
 	}
 
 	req, err := http.NewRequestWithContext(
 		ctx,
-		"POST",
+		method,
 		opts.URLBase+urlPath,
-		buf,
+		reqBody,
 	)
 	if err != nil {
 		return err
 	}
-	req.Header.Set("Content-Type", "application/json")
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
 	if reqModify != nil {
 		req = reqModify(req)
 	}
@@ -125,6 +164,7 @@ func postJSON(ctx context.Context, urlPath string, reqModify func(*http.Request)
 	if err != nil {
 		return err
 	}
+	defer resp.Body.Close()
 
 	if err := json.NewDecoder(resp.Body).Decode(result); err != nil {
 		return err
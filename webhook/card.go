@@ -0,0 +1,191 @@
+package webhook
+
+import (
+	"crypto/sha1"
+	"crypto/subtle"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+
+	"github.com/huangjunwen/feishu-driver/conf"
+)
+
+var (
+	_ http.Handler = (*CardHandler)(nil)
+)
+
+// CardAction 是消息卡片交互回调的内容，详见 https://open.feishu.cn/document/ukTMukTMukTM/uYzM3QjL2MzN04iNzcDN/configuring-card-callbacks/card-callback-structure
+type CardAction struct {
+	OpenId        string `json:"open_id"`
+	UserId        string `json:"user_id"`
+	OpenMessageId string `json:"open_message_id"`
+	OpenChatId    string `json:"open_chat_id"`
+	TenantKey     string `json:"tenant_key"`
+
+	// Token 是用于更新卡片的 token, 注意不是 Verification Token
+	Token string `json:"token"`
+
+	Action struct {
+		// Value 是交互元素上开发者自定义的 value, 可用 DecodeValue 解析
+		Value json.RawMessage `json:"value"`
+
+		// Tag 是交互元素的类型, 如 button/select_static/overflow/date_picker 等
+		Tag string `json:"tag"`
+
+		// Option 是选择类交互元素选中的值
+		Option string `json:"option"`
+
+		// Timezone 是时间选择类交互元素的时区
+		Timezone string `json:"timezone"`
+	} `json:"action"`
+}
+
+// CardActionHandler 用于处理卡片交互回调，返回的 card 非 nil 时会以 json 编码作为响应,
+// 用于更新卡片; 返回 nil 则卡片保持不变
+type CardActionHandler func(r *http.Request, action *CardAction) (card interface{}, err error)
+
+// CardHandler 是一个 http.Handler，用于处理消息卡片请求网址的回调, 会校验签名/自动处理 url_verification
+type CardHandler struct {
+	verifToken string
+	decrypter  *decrypter
+	handler    CardActionHandler
+}
+
+// DecodeValue 将 Action.Value 解析到 v 中
+func (action *CardAction) DecodeValue(v interface{}) error {
+	if len(action.Action.Value) == 0 {
+		return fmt.Errorf("No action value")
+	}
+	return json.Unmarshal(action.Action.Value, v)
+}
+
+// NewCardHandler 创建一个 CardHandler，cnf 必须提供，handler 用于处理卡片交互，
+// 也可以传入 nil
+func NewCardHandler(cnf conf.WebhookConfig, handler CardActionHandler) *CardHandler {
+
+	verifToken := cnf.FeishuWebhookVerifToken()
+	if verifToken == "" {
+		panic(fmt.Errorf("Empty feishu verify token"))
+	}
+
+	var decrypter *decrypter
+	if key := cnf.FeishuWebhookEncryptKey(); key != "" {
+		decrypter = newDecrypter(key)
+	}
+
+	if handler == nil {
+		handler = func(r *http.Request, action *CardAction) (interface{}, error) {
+			return nil, nil
+		}
+	}
+
+	return &CardHandler{
+		verifToken: verifToken,
+		decrypter:  decrypter,
+		handler:    handler,
+	}
+
+}
+
+// ServeHTTP 满足 http.Handler 接口
+func (h *CardHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+
+	body, err := ioutil.ReadAll(r.Body)
+	if err != nil {
+		http.Error(w, err.Error(), 500)
+		return
+	}
+
+	invalidPayload := func(code int) {
+		http.Error(w, "Invalid payload", code)
+	}
+
+	// 带签名的是卡片交互回调; 不带签名的只能是 url_verification
+	signed := r.Header.Get(HeaderSignature) != ""
+	if signed && !h.verifySignature(r.Header, body) {
+		invalidPayload(StatusInvalidToken)
+		return
+	}
+
+	// url_verification 在设置了 Encrypt Key 时也会加密
+	if h.decrypter != nil && !signed {
+		body, err = h.decrypter.DecryptBody(body)
+		if err != nil {
+			invalidPayload(StatusDecryptError)
+			return
+		}
+	}
+
+	verification := &struct {
+		Type      string `json:"type"`
+		Token     string `json:"token"`
+		Challenge string `json:"challenge"`
+	}{}
+	if err := json.Unmarshal(body, verification); err != nil {
+		invalidPayload(StatusInvalidPayload)
+		return
+	}
+
+	if verification.Type == PayloadTypeURLVerification {
+		if verification.Token != h.verifToken {
+			invalidPayload(StatusInvalidToken)
+			return
+		}
+		// NOTE: 这里忽略错误
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"challenge": verification.Challenge,
+		})
+		return
+	}
+
+	if !signed {
+		invalidPayload(StatusInvalidToken)
+		return
+	}
+
+	action := new(CardAction)
+	if err := json.Unmarshal(body, action); err != nil {
+		invalidPayload(StatusInvalidPayload)
+		return
+	}
+
+	card, err := h.handler(r, action)
+	if err != nil {
+		http.Error(w, err.Error(), 500)
+		return
+	}
+	if card == nil {
+		card = struct{}{}
+	}
+
+	resp, err := json.Marshal(card)
+	if err != nil {
+		http.Error(w, err.Error(), 500)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.Write(resp)
+}
+
+// verifySignature 校验卡片回调签名: sha1(timestamp + nonce + verifToken + body)
+func (h *CardHandler) verifySignature(header http.Header, body []byte) bool {
+	expected := CardSignature(
+		header.Get(HeaderRequestTimestamp),
+		header.Get(HeaderRequestNonce),
+		h.verifToken,
+		body,
+	)
+	return subtle.ConstantTimeCompare([]byte(expected), []byte(header.Get(HeaderSignature))) == 1
+}
+
+// CardSignature 计算卡片回调的签名，详见 https://open.feishu.cn/document/ukTMukTMukTM/uYzM3QjL2MzN04iNzcDN/configuring-card-callbacks/card-callback-structure
+func CardSignature(timestamp, nonce, verifToken string, body []byte) string {
+	hash := sha1.New()
+	hash.Write([]byte(timestamp))
+	hash.Write([]byte(nonce))
+	hash.Write([]byte(verifToken))
+	hash.Write(body)
+	return hex.EncodeToString(hash.Sum(nil))
+}
@@ -0,0 +1,112 @@
+package webhook
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/huangjunwen/feishu-driver/conf"
+)
+
+func TestCardHandler(t *testing.T) {
+	assert := assert.New(t)
+
+	verifToken := "GzhQEyfUcx7eEungQFWtXgCbxSpUOJIb"
+	h := NewCardHandler(conf.NewWebhookConfig(verifToken, ""), func(r *http.Request, action *CardAction) (interface{}, error) {
+		value := map[string]string{}
+		if err := action.DecodeValue(&value); err != nil {
+			return nil, err
+		}
+		if value["key"] == "keep" {
+			return nil, nil
+		}
+		return map[string]interface{}{
+			"header": map[string]interface{}{
+				"title": map[string]interface{}{
+					"tag":     "plain_text",
+					"content": value["key"] + " by " + action.OpenId,
+				},
+			},
+		}, nil
+	})
+
+	post := func(body string, header map[string]string) *httptest.ResponseRecorder {
+		r := httptest.NewRequest("POST", "/card", bytes.NewBufferString(body))
+		for k, v := range header {
+			r.Header.Set(k, v)
+		}
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, r)
+		return w
+	}
+
+	signedHeader := func(body string) map[string]string {
+		return map[string]string{
+			HeaderRequestTimestamp: "1608725989",
+			HeaderRequestNonce:     "2b9c5a2f-1a2c-4e2c-9a3c-0f7f4e0b6c1a",
+			HeaderSignature:        CardSignature("1608725989", "2b9c5a2f-1a2c-4e2c-9a3c-0f7f4e0b6c1a", verifToken, []byte(body)),
+		}
+	}
+
+	actionBody := func(key string) string {
+		return `{
+			"open_id": "ou_2ef04637d933f798dcb92c99e845ed09",
+			"user_id": "75ge6c49",
+			"open_message_id": "om_d0b9b9d3c7f2a5d5b8a0c7f4d2e8a1b3",
+			"tenant_key": "2d520d3b434f175e",
+			"token": "c-295ee57216a5dc9de90fefd0aadb4b1d7d424f29",
+			"action": {
+				"value": {"key": "` + key + `"},
+				"tag": "button"
+			}
+		}`
+	}
+
+	// url_verification
+	{
+		w := post(`{"challenge": "ajls384kdjx98XX", "token": "`+verifToken+`", "type": "url_verification"}`, nil)
+		assert.Equal(200, w.Code)
+		assertJSONEqual(assert, []byte(`{"challenge": "ajls384kdjx98XX"}`), w.Body.Bytes())
+	}
+
+	// url_verification token 错误
+	{
+		w := post(`{"challenge": "ajls384kdjx98XX", "token": "xxx", "type": "url_verification"}`, nil)
+		assert.Equal(463, w.Code)
+	}
+
+	// 返回新卡片
+	{
+		body := actionBody("approve")
+		w := post(body, signedHeader(body))
+		assert.Equal(200, w.Code)
+		assertJSONEqual(assert, []byte(`{"header": {"title": {"tag": "plain_text", "content": "approve by ou_2ef04637d933f798dcb92c99e845ed09"}}}`), w.Body.Bytes())
+	}
+
+	// 卡片不变
+	{
+		body := actionBody("keep")
+		w := post(body, signedHeader(body))
+		assert.Equal(200, w.Code)
+		assertJSONEqual(assert, []byte(`{}`), w.Body.Bytes())
+	}
+
+	// 签名错误
+	{
+		body := actionBody("approve")
+		header := signedHeader(body)
+		header[HeaderSignature] = "0000"
+		w := post(body, header)
+		assert.Equal(463, w.Code)
+	}
+
+	// 无签名
+	{
+		w := post(actionBody("approve"), nil)
+		assert.Equal(463, w.Code)
+	}
+
+}
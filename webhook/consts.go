@@ -4,3 +4,20 @@ var (
 	PayloadTypeURLVerification = "url_verification"
 	PayloadTypeEventCallback   = "event_callback"
 )
+
+var (
+	HeaderRequestTimestamp = "X-Lark-Request-Timestamp"
+	HeaderRequestNonce     = "X-Lark-Request-Nonce"
+	HeaderSignature        = "X-Lark-Signature"
+)
+
+var (
+	// StatusDecryptError 是解密失败时的响应状态码
+	StatusDecryptError = 461
+
+	// StatusInvalidPayload 是 payload 解析失败时的响应状态码
+	StatusInvalidPayload = 462
+
+	// StatusInvalidToken 是 token 或签名校验失败时的响应状态码
+	StatusInvalidToken = 463
+)
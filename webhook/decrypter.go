@@ -5,6 +5,7 @@ import (
 	"crypto/cipher"
 	"crypto/sha256"
 	"encoding/base64"
+	"encoding/json"
 	"fmt"
 )
 
@@ -51,9 +52,28 @@ func (d *decrypter) Decrypt(b64CipherText string) ([]byte, error) {
 	plainText := make([]byte, len(cipherText))
 	cipher.NewCBCDecrypter(d.block, iv).CryptBlocks(plainText, cipherText)
 
-	// unpad
-	pad := int(plainText[len(plainText)-1])
+	// unpad (PKCS#7), 秘钥错误时解密结果通常是乱码, padding 校验会失败
+	pad := int(plainText[l-1])
+	if pad < 1 || pad > blockSize || pad > l {
+		return nil, fmt.Errorf("Invalid padding")
+	}
+	for _, b := range plainText[l-pad:] {
+		if int(b) != pad {
+			return nil, fmt.Errorf("Invalid padding")
+		}
+	}
 	plainText = plainText[:l-pad]
 
 	return plainText, nil
 }
+
+// DecryptBody 解密形如 {"encrypt": "..."} 的请求 body
+func (d *decrypter) DecryptBody(body []byte) ([]byte, error) {
+	encryptPayload := &struct {
+		Encrypt string `json:"encrypt"`
+	}{}
+	if err := json.Unmarshal(body, encryptPayload); err != nil {
+		return nil, err
+	}
+	return d.Decrypt(encryptPayload.Encrypt)
+}
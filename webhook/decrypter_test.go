@@ -1,6 +1,9 @@
 package webhook
 
 import (
+	"crypto/aes"
+	"crypto/cipher"
+	"encoding/base64"
 	"encoding/json"
 	"fmt"
 	"testing"
@@ -79,3 +82,55 @@ func TestDecrypter(t *testing.T) {
 	}
 
 }
+
+func TestEncrypt(t *testing.T) {
+	assert := assert.New(t)
+
+	d := newDecrypter("kudryavka")
+	for _, plainText := range []string{
+		"",
+		"a",
+		"0123456789abcde",
+		"0123456789abcdef",
+		`{"challenge": "ajls384kdjx98XX", "token": "xxxxxx", "type": "url_verification"}`,
+	} {
+		cipherText, err := Encrypt("kudryavka", []byte(plainText))
+		assert.NoError(err)
+
+		decrypted, err := d.Decrypt(cipherText)
+		assert.NoError(err)
+		assert.Equal(plainText, string(decrypted))
+	}
+}
+
+func TestDecryptInvalidPadding(t *testing.T) {
+	assert := assert.New(t)
+
+	key := "kudryavka"
+	d := newDecrypter(key)
+	encrypt := func(plainText []byte) string {
+		iv := make([]byte, aes.BlockSize)
+		cipherText := make([]byte, len(plainText))
+		cipher.NewCBCEncrypter(d.block, iv).CryptBlocks(cipherText, plainText)
+		return base64.StdEncoding.EncodeToString(append(iv, cipherText...))
+	}
+
+	for _, plainText := range []string{
+		"xxxxxxxxxxxxxxxx",                    // pad 过大 (以前会 panic)
+		"xxxxxxxxxxxxxxx\x00",                 // pad 为 0
+		"xxxxxxxxxxxxx\x01\x02\x03",           // pad 字节不一致
+		"xxxxxxxxxxxxxxxxxxxxxxxxxxxxxxx\x11", // pad 大于 block size
+	} {
+		_, err := d.Decrypt(encrypt([]byte(plainText)))
+		assert.Error(err, "%q", plainText)
+	}
+
+	plainText, err := d.Decrypt(encrypt([]byte("xxxxxxxxxxxxxx\x02\x02")))
+	assert.NoError(err)
+	assert.Equal("xxxxxxxxxxxxxx", string(plainText))
+
+	// 使用错误的秘钥解密
+	encrypted, err := Encrypt("another", []byte(`{"token": "x"}`))
+	assert.NoError(err)
+	assert.NotPanics(func() { d.Decrypt(encrypted) })
+}
@@ -0,0 +1,36 @@
+package webhook
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"io"
+)
+
+// Encrypt 是 decrypter 的逆操作，使用 Encrypt Key 以相同的方式 (AES-256-CBC, 随机 iv) 加密 plainText,
+// 返回 base64 编码的密文，主要用于测试
+func Encrypt(key string, plainText []byte) (string, error) {
+	sum := sha256.Sum256([]byte(key))
+	block, err := aes.NewCipher(sum[:]) // AES-256
+	if err != nil {
+		return "", err
+	}
+	blockSize := block.BlockSize()
+
+	// pad
+	pad := blockSize - len(plainText)%blockSize
+	plainText = append(append([]byte{}, plainText...), bytes.Repeat([]byte{byte(pad)}, pad)...)
+
+	// 第一个 block 是 iv
+	cipherText := make([]byte, blockSize+len(plainText))
+	iv := cipherText[:blockSize]
+	if _, err := io.ReadFull(rand.Reader, iv); err != nil {
+		return "", err
+	}
+	cipher.NewCBCEncrypter(block, iv).CryptBlocks(cipherText[blockSize:], plainText)
+
+	return base64.StdEncoding.EncodeToString(cipherText), nil
+}
@@ -1,17 +1,84 @@
 package webhook
 
 import (
+	"sort"
+	"sync"
+
 	"github.com/huangjunwen/feishu-driver/webhook/events"
 )
 
 var (
-	newEvMaps = map[string]func() interface{}{}
+	// DefaultRegistry 是全局默认的事件类型注册表，包含 events.Regist 中的所有事件类型，
+	// 未使用 HRegistry 选项的 Handler 均使用它
+	DefaultRegistry = NewRegistry()
 )
 
-// RegistEv 注册一种订阅事件类型，typ 是 payload["event"]["type"] 字段, newEv 是工厂函数，
-// 用于创建一个新的该类型事件
+// Registry 是事件类型注册表，记录事件类型到工厂函数的映射，可并发使用
+type Registry struct {
+	mu     sync.RWMutex
+	newEvs map[string]func() interface{}
+}
+
+// NewRegistry 创建一个空的 Registry, 可以使用 events.Regist(r.Regist) 填入内置的事件类型
+func NewRegistry() *Registry {
+	return &Registry{
+		newEvs: map[string]func() interface{}{},
+	}
+}
+
+// Regist 注册一种订阅事件类型 (已存在则覆盖)，typ 是事件类型：1.0 版本是 payload["event"]["type"] 字段，
+// 2.0 版本是 payload["header"]["event_type"] 字段; newEv 是工厂函数，用于创建一个新的该类型事件;
+// typ 为 "" 表示未注册类型时使用的工厂函数
+func (r *Registry) Regist(typ string, newEv func() interface{}) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.newEvs[typ] = newEv
+}
+
+// NewEvent 创建一个 typ 类型的新事件，typ 未注册时使用 "" 类型的工厂函数，若都没有返回 events.Unsupported
+func (r *Registry) NewEvent(typ string) interface{} {
+	r.mu.RLock()
+	newEv := r.newEvs[typ]
+	if newEv == nil {
+		newEv = r.newEvs[""]
+	}
+	r.mu.RUnlock()
+
+	if newEv == nil {
+		return new(events.Unsupported)
+	}
+	return newEv()
+}
+
+// Types 返回所有已注册的事件类型 (不包括 "")
+func (r *Registry) Types() []string {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	typs := make([]string, 0, len(r.newEvs))
+	for typ := range r.newEvs {
+		if typ != "" {
+			typs = append(typs, typ)
+		}
+	}
+	sort.Strings(typs)
+	return typs
+}
+
+// Clone 复制一个 Registry, 之后两者的注册互不影响
+func (r *Registry) Clone() *Registry {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	ret := NewRegistry()
+	for typ, newEv := range r.newEvs {
+		ret.newEvs[typ] = newEv
+	}
+	return ret
+}
+
+// RegistEv 在 DefaultRegistry 中注册一种订阅事件类型，见 Registry.Regist;
+// 它会影响所有使用 DefaultRegistry 的 Handler，只想影响单个 Handler 时请使用 HRegistEv 选项
 func RegistEv(typ string, newEv func() interface{}) {
-	newEvMaps[typ] = newEv
+	DefaultRegistry.Regist(typ, newEv)
 }
 
 func init() {
@@ -0,0 +1,180 @@
+package events
+
+// Approval 审批通过 https://open.feishu.cn/document/ukTMukTMukTM/uIDO24iM4YjLygjN/event/common-event/approval-event
+type Approval struct {
+	AppId          string `json:"app_id"`
+	TenantKey      string `json:"tenant_key"`
+	DefinitionCode string `json:"definition_code"`
+	DefinitionName string `json:"definition_name"`
+	InstanceCode   string `json:"instance_code"`
+	StartTime      int64  `json:"start_time"`
+	EndTime        int64  `json:"end_time"`
+	Event          string `json:"event"`
+}
+
+// ApprovalInstance 审批实例状态变更 https://open.feishu.cn/document/ukTMukTMukTM/uIDO24iM4YjLygjN/event/common-event/approval-instance-event
+type ApprovalInstance struct {
+	AppId               string `json:"app_id"`
+	TenantKey           string `json:"tenant_key"`
+	ApprovalCode        string `json:"approval_code"`
+	InstanceCode        string `json:"instance_code"`
+	Status              string `json:"status"`
+	OperateTime         string `json:"operate_time"`
+	InstanceOperateTime string `json:"instance_operate_time"`
+}
+
+// ApprovalTask 审批任务状态变更 https://open.feishu.cn/document/ukTMukTMukTM/uIDO24iM4YjLygjN/event/common-event/approval-task-event
+type ApprovalTask struct {
+	AppId        string `json:"app_id"`
+	TenantKey    string `json:"tenant_key"`
+	ApprovalCode string `json:"approval_code"`
+	InstanceCode string `json:"instance_code"`
+	TaskId       string `json:"task_id"`
+	UserId       string `json:"user_id"`
+	OpenId       string `json:"open_id"`
+	Status       string `json:"status"`
+	OperateTime  string `json:"operate_time"`
+	CustomKey    string `json:"custom_key"`
+	DefKey       string `json:"def_key"`
+	Extra        string `json:"extra"`
+}
+
+// ApprovalCc 审批抄送状态变更 https://open.feishu.cn/document/ukTMukTMukTM/uIDO24iM4YjLygjN/event/common-event/approval-cc-event
+type ApprovalCc struct {
+	AppId        string `json:"app_id"`
+	TenantKey    string `json:"tenant_key"`
+	ApprovalCode string `json:"approval_code"`
+	InstanceCode string `json:"instance_code"`
+	Id           string `json:"id"`
+	UserId       string `json:"user_id"`
+	CreateTime   string `json:"create_time"`
+	From         string `json:"from"`
+	Extra        string `json:"extra"`
+}
+
+// LeaveApproval 请假审批 https://open.feishu.cn/document/ukTMukTMukTM/uIDO24iM4YjLygjN/event/common-event/leave
+type LeaveApproval struct {
+	AppId          string `json:"app_id"`
+	TenantKey      string `json:"tenant_key"`
+	InstanceCode   string `json:"instance_code"`
+	EmployeeId     string `json:"employee_id"`
+	OpenId         string `json:"open_id"`
+	StartTime      int64  `json:"start_time"`
+	EndTime        int64  `json:"end_time"`
+	LeaveType      string `json:"leave_type"`
+	LeaveUnit      int    `json:"leave_unit"`
+	LeaveStartTime string `json:"leave_start_time"`
+	LeaveEndTime   string `json:"leave_end_time"`
+	LeaveInterval  int64  `json:"leave_interval"`
+	LeaveReason    string `json:"leave_reason"`
+}
+
+// LeaveApprovalV2 请假审批 (新版) https://open.feishu.cn/document/ukTMukTMukTM/uIDO24iM4YjLygjN/event/common-event/leave-approval
+type LeaveApprovalV2 struct {
+	AppId          string                 `json:"app_id"`
+	TenantKey      string                 `json:"tenant_key"`
+	InstanceCode   string                 `json:"instance_code"`
+	UserId         string                 `json:"user_id"`
+	OpenId         string                 `json:"open_id"`
+	StartTime      int64                  `json:"start_time"`
+	EndTime        int64                  `json:"end_time"`
+	LeaveName      string                 `json:"leave_name"`
+	LeaveUnit      string                 `json:"leave_unit"`
+	LeaveStartTime string                 `json:"leave_start_time"`
+	LeaveEndTime   string                 `json:"leave_end_time"`
+	LeaveInterval  int64                  `json:"leave_interval"`
+	LeaveReason    string                 `json:"leave_reason"`
+	I18nResources  []ApprovalI18nResource `json:"i18n_resources"`
+}
+
+// WorkApproval 加班审批 https://open.feishu.cn/document/ukTMukTMukTM/uIDO24iM4YjLygjN/event/common-event/overtime
+type WorkApproval struct {
+	AppId         string `json:"app_id"`
+	TenantKey     string `json:"tenant_key"`
+	InstanceCode  string `json:"instance_code"`
+	EmployeeId    string `json:"employee_id"`
+	OpenId        string `json:"open_id"`
+	StartTime     int64  `json:"start_time"`
+	EndTime       int64  `json:"end_time"`
+	WorkType      string `json:"work_type"`
+	WorkStartTime string `json:"work_start_time"`
+	WorkEndTime   string `json:"work_end_time"`
+	WorkInterval  int64  `json:"work_interval"`
+	WorkReason    string `json:"work_reason"`
+}
+
+// ShiftApproval 换班审批 https://open.feishu.cn/document/ukTMukTMukTM/uIDO24iM4YjLygjN/event/common-event/shift-change
+type ShiftApproval struct {
+	AppId        string `json:"app_id"`
+	TenantKey    string `json:"tenant_key"`
+	InstanceCode string `json:"instance_code"`
+	EmployeeId   string `json:"employee_id"`
+	OpenId       string `json:"open_id"`
+	StartTime    int64  `json:"start_time"`
+	EndTime      int64  `json:"end_time"`
+	ShiftTime    string `json:"shift_time"`
+	ReturnTime   string `json:"return_time"`
+	ShiftReason  string `json:"shift_reason"`
+}
+
+// RemedyApproval 补卡审批 https://open.feishu.cn/document/ukTMukTMukTM/uIDO24iM4YjLygjN/event/common-event/attendance-record-correction
+type RemedyApproval struct {
+	AppId        string `json:"app_id"`
+	TenantKey    string `json:"tenant_key"`
+	InstanceCode string `json:"instance_code"`
+	EmployeeId   string `json:"employee_id"`
+	OpenId       string `json:"open_id"`
+	StartTime    int64  `json:"start_time"`
+	EndTime      int64  `json:"end_time"`
+	RemedyTime   string `json:"remedy_time"`
+	RemedyReason string `json:"remedy_reason"`
+}
+
+// TripApproval 出差审批 https://open.feishu.cn/document/ukTMukTMukTM/uIDO24iM4YjLygjN/event/common-event/business-trip
+type TripApproval struct {
+	AppId        string `json:"app_id"`
+	TenantKey    string `json:"tenant_key"`
+	InstanceCode string `json:"instance_code"`
+	EmployeeId   string `json:"employee_id"`
+	OpenId       string `json:"open_id"`
+	StartTime    int64  `json:"start_time"`
+	EndTime      int64  `json:"end_time"`
+	Schedules    []struct {
+		TripStartTime  string `json:"trip_start_time"`
+		TripEndTime    string `json:"trip_end_time"`
+		TripInterval   int64  `json:"trip_interval"`
+		Departure      string `json:"departure"`
+		Destination    string `json:"destination"`
+		Transportation string `json:"transportation"`
+		TripType       string `json:"trip_type"`
+		Remark         string `json:"remark"`
+	} `json:"schedules"`
+	TripInterval int64    `json:"trip_interval"`
+	TripReason   string   `json:"trip_reason"`
+	TripPeers    []string `json:"trip_peers"`
+}
+
+// OutApproval 外出审批 https://open.feishu.cn/document/ukTMukTMukTM/uIDO24iM4YjLygjN/event/common-event/out-of-office
+type OutApproval struct {
+	AppId         string                 `json:"app_id"`
+	TenantKey     string                 `json:"tenant_key"`
+	InstanceCode  string                 `json:"instance_code"`
+	UserId        string                 `json:"user_id"`
+	OpenId        string                 `json:"open_id"`
+	StartTime     int64                  `json:"start_time"`
+	EndTime       int64                  `json:"end_time"`
+	OutName       string                 `json:"out_name"`
+	OutUnit       string                 `json:"out_unit"`
+	OutStartTime  string                 `json:"out_start_time"`
+	OutEndTime    string                 `json:"out_end_time"`
+	OutInterval   int64                  `json:"out_interval"`
+	OutReason     string                 `json:"out_reason"`
+	I18nResources []ApprovalI18nResource `json:"i18n_resources"`
+}
+
+// ApprovalI18nResource 是审批事件中的多语言资源, Texts 的 key 形如 @i18n@xxx
+type ApprovalI18nResource struct {
+	Locale    string            `json:"locale"`
+	IsDefault bool              `json:"is_default"`
+	Texts     map[string]string `json:"texts"`
+}
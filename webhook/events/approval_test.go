@@ -0,0 +1,266 @@
+package events
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// decodeEvent 使用 Regist 中注册的类型解析事件
+func decodeEvent(assert *assert.Assertions, typ string, rawEvent string) interface{} {
+	var ev interface{}
+	Regist(func(t string, newEv func() interface{}) {
+		if t == typ {
+			ev = newEv()
+		}
+	})
+	if !assert.NotNil(ev, "event type %q not registered", typ) {
+		return nil
+	}
+	assert.NoError(json.Unmarshal([]byte(rawEvent), ev), typ)
+	return ev
+}
+
+func TestApprovalEvents(t *testing.T) {
+	assert := assert.New(t)
+
+	// 以下样例取自 https://open.feishu.cn/document/ukTMukTMukTM/uIDO24iM4YjLygjN/event/common-event/approval-event
+	{
+		ev := decodeEvent(assert, "approval", `{
+			"app_id": "cli_xxx",
+			"tenant_key": "xxx",
+			"type": "approval",
+			"definition_code": "E78F1022-A166-447C-8320-E151DA90D70F",
+			"definition_name": "请假",
+			"instance_code": "59558CEE-CEF4-45C9-1F7A-9A4D7C7F5B3E",
+			"start_time": 1502199207,
+			"end_time": 1502199307,
+			"event": "approve"
+		}`).(*Approval)
+		assert.Equal("E78F1022-A166-447C-8320-E151DA90D70F", ev.DefinitionCode)
+		assert.Equal(int64(1502199307), ev.EndTime)
+		assert.Equal("approve", ev.Event)
+	}
+
+	{
+		ev := decodeEvent(assert, "approval_instance", `{
+			"app_id": "cli_xxx",
+			"approval_code": "7C468A54-8745-2245-9675-08B7C63E7A85",
+			"instance_code": "81D31358-93AF-92D6-7425-01A5D67C4E71",
+			"instance_operate_time": "1666079207003",
+			"operate_time": "1666079207003",
+			"status": "PENDING",
+			"tenant_key": "xxx",
+			"type": "approval_instance"
+		}`).(*ApprovalInstance)
+		assert.Equal("81D31358-93AF-92D6-7425-01A5D67C4E71", ev.InstanceCode)
+		assert.Equal("PENDING", ev.Status)
+		assert.Equal("1666079207003", ev.InstanceOperateTime)
+	}
+
+	{
+		ev := decodeEvent(assert, "approval_task", `{
+			"app_id": "cli_xxx",
+			"open_id": "ou_123456",
+			"tenant_key": "xxx",
+			"type": "approval_task",
+			"approval_code": "7C468A54-8745-2245-9675-08B7C63E7A85",
+			"instance_code": "81D31358-93AF-92D6-7425-01A5D67C4E71",
+			"task_id": "12345",
+			"user_id": "b8ebc7a6",
+			"status": "APPROVED",
+			"operate_time": "1502199207000",
+			"custom_key": "xxx",
+			"def_key": "xxx",
+			"extra": "xxx"
+		}`).(*ApprovalTask)
+		assert.Equal("12345", ev.TaskId)
+		assert.Equal("APPROVED", ev.Status)
+		assert.Equal("ou_123456", ev.OpenId)
+	}
+
+	{
+		ev := decodeEvent(assert, "approval_cc", `{
+			"app_id": "cli_xxx",
+			"tenant_key": "xxx",
+			"type": "approval_cc",
+			"approval_code": "7C468A54-8745-2245-9675-08B7C63E7A85",
+			"instance_code": "81D31358-93AF-92D6-7425-01A5D67C4E71",
+			"id": "1234",
+			"user_id": "b8ebc7a6",
+			"create_time": "1502199207000",
+			"from": "b8ebc7a6",
+			"extra": ""
+		}`).(*ApprovalCc)
+		assert.Equal("1234", ev.Id)
+		assert.Equal("b8ebc7a6", ev.From)
+	}
+
+	{
+		ev := decodeEvent(assert, "leave_approval", `{
+			"app_id": "cli_xxx",
+			"tenant_key": "xxx",
+			"type": "leave_approval",
+			"instance_code": "xxx",
+			"employee_id": "xxx",
+			"open_id": "ou_xxx",
+			"start_time": 1502199207,
+			"end_time": 1502199307,
+			"leave_type": "事假",
+			"leave_unit": 1,
+			"leave_start_time": "2018-12-01 12:00:00",
+			"leave_end_time": "2018-12-02 12:00:00",
+			"leave_interval": 7200,
+			"leave_reason": "abc"
+		}`).(*LeaveApproval)
+		assert.Equal("事假", ev.LeaveType)
+		assert.Equal(1, ev.LeaveUnit)
+		assert.Equal(int64(7200), ev.LeaveInterval)
+	}
+
+	{
+		ev := decodeEvent(assert, "leave_approvalV2", `{
+			"app_id": "cli_xxx",
+			"tenant_key": "xxx",
+			"type": "leave_approvalV2",
+			"instance_code": "xxx",
+			"user_id": "xxx",
+			"open_id": "ou_xxx",
+			"start_time": 1502199207,
+			"end_time": 1502199307,
+			"leave_name": "@i18n@123456",
+			"leave_unit": "DAY",
+			"leave_start_time": "2020-05-07 00:00:00",
+			"leave_end_time": "2020-05-08 00:00:00",
+			"leave_interval": 86400,
+			"leave_reason": "abc",
+			"i18n_resources": [
+				{
+					"locale": "en_us",
+					"is_default": true,
+					"texts": {"@i18n@123456": "Holiday"}
+				}
+			]
+		}`).(*LeaveApprovalV2)
+		assert.Equal("DAY", ev.LeaveUnit)
+		if assert.Len(ev.I18nResources, 1) {
+			assert.True(ev.I18nResources[0].IsDefault)
+			assert.Equal("Holiday", ev.I18nResources[0].Texts[ev.LeaveName])
+		}
+	}
+
+	{
+		ev := decodeEvent(assert, "work_approval", `{
+			"app_id": "cli_xxx",
+			"tenant_key": "xxx",
+			"type": "work_approval",
+			"instance_code": "xxx",
+			"employee_id": "xxx",
+			"open_id": "ou_xxx",
+			"start_time": 1502199207,
+			"end_time": 1502199307,
+			"work_type": "xxx",
+			"work_start_time": "2018-12-01 12:00:00",
+			"work_end_time": "2018-12-02 12:00:00",
+			"work_interval": 7200,
+			"work_reason": "xxx"
+		}`).(*WorkApproval)
+		assert.Equal("2018-12-01 12:00:00", ev.WorkStartTime)
+		assert.Equal(int64(7200), ev.WorkInterval)
+	}
+
+	{
+		ev := decodeEvent(assert, "shift_approval", `{
+			"app_id": "cli_xxx",
+			"tenant_key": "xxx",
+			"type": "shift_approval",
+			"instance_code": "xxx",
+			"employee_id": "xxx",
+			"open_id": "ou_xxx",
+			"start_time": 1502199207,
+			"end_time": 1502199307,
+			"shift_time": "2018-12-01 12:00:00",
+			"return_time": "2018-12-02 12:00:00",
+			"shift_reason": "xxx"
+		}`).(*ShiftApproval)
+		assert.Equal("2018-12-02 12:00:00", ev.ReturnTime)
+	}
+
+	{
+		ev := decodeEvent(assert, "remedy_approval", `{
+			"app_id": "cli_xxx",
+			"tenant_key": "xxx",
+			"type": "remedy_approval",
+			"instance_code": "xxx",
+			"employee_id": "xxx",
+			"open_id": "ou_xxx",
+			"start_time": 1502199207,
+			"end_time": 1502199307,
+			"remedy_time": "2018-12-01 12:00:00",
+			"remedy_reason": "xxx"
+		}`).(*RemedyApproval)
+		assert.Equal("2018-12-01 12:00:00", ev.RemedyTime)
+	}
+
+	{
+		ev := decodeEvent(assert, "trip_approval", `{
+			"app_id": "cli_xxx",
+			"tenant_key": "xxx",
+			"type": "trip_approval",
+			"instance_code": "xxx",
+			"employee_id": "xxx",
+			"open_id": "ou_xxx",
+			"start_time": 1502199207,
+			"end_time": 1502199307,
+			"schedules": [
+				{
+					"trip_start_time": "2018-12-01 12:00:00",
+					"trip_end_time": "2018-12-02 12:00:00",
+					"trip_interval": 3600,
+					"departure": "xxx",
+					"destination": "xxx",
+					"transportation": "xxx",
+					"trip_type": "单程",
+					"remark": "备注"
+				}
+			],
+			"trip_interval": 3600,
+			"trip_reason": "xxx",
+			"trip_peers": ["xxx", "yyy"]
+		}`).(*TripApproval)
+		if assert.Len(ev.Schedules, 1) {
+			assert.Equal("单程", ev.Schedules[0].TripType)
+		}
+		assert.Equal([]string{"xxx", "yyy"}, ev.TripPeers)
+	}
+
+	{
+		ev := decodeEvent(assert, "out_approval", `{
+			"app_id": "cli_xxx",
+			"tenant_key": "xxx",
+			"type": "out_approval",
+			"instance_code": "xxx",
+			"user_id": "xxx",
+			"open_id": "ou_xxx",
+			"start_time": 1502199207,
+			"end_time": 1502199307,
+			"out_name": "@i18n@123456",
+			"out_unit": "HOUR",
+			"out_start_time": "2020-05-07 10:00:00",
+			"out_end_time": "2020-05-07 12:00:00",
+			"out_interval": 7200,
+			"out_reason": "xxx",
+			"i18n_resources": [
+				{
+					"locale": "zh_cn",
+					"is_default": true,
+					"texts": {"@i18n@123456": "外出"}
+				}
+			]
+		}`).(*OutApproval)
+		assert.Equal("HOUR", ev.OutUnit)
+		assert.Equal("外出", ev.I18nResources[0].Texts[ev.OutName])
+	}
+
+}
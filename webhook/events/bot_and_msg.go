@@ -6,7 +6,7 @@ type AddBot struct {
 	TenantKey           string            `json:"tenant_key"`
 	ChatI18nNames       map[string]string `json:"chat_i18n_names"`
 	ChatName            string            `json:"chat_name"`
-	ChatOwnerEmployeeId string            `json:"chat_owner_employee_id`
+	ChatOwnerEmployeeId string            `json:"chat_owner_employee_id"`
 	ChatOwnerName       string            `json:"chat_owner_name"`
 	ChatOwnerOpenId     string            `json:"chat_owner_open_id"`
 	OpenChatId          string            `json:"open_chat_id"`
@@ -22,7 +22,7 @@ type RemoveBot struct {
 	TenantKey           string            `json:"tenant_key"`
 	ChatI18nNames       map[string]string `json:"chat_i18n_names"`
 	ChatName            string            `json:"chat_name"`
-	ChatOwnerEmployeeId string            `json:"chat_owner_employee_id`
+	ChatOwnerEmployeeId string            `json:"chat_owner_employee_id"`
 	ChatOwnerName       string            `json:"chat_owner_name"`
 	ChatOwnerOpenId     string            `json:"chat_owner_open_id"`
 	OpenChatId          string            `json:"open_chat_id"`
@@ -52,7 +52,7 @@ type P2pChatCreate struct {
 type Message struct {
 	AppId            string   `json:"app_id"`
 	TenantKey        string   `json:"tenant_key"`
-	RootId           string   `json:root_id`
+	RootId           string   `json:"root_id"`
 	ParentId         string   `json:"parent_id"`
 	OpenChatId       string   `json:"open_chat_id"`
 	ChatType         string   `json:"chat_type"`
@@ -64,7 +64,7 @@ type Message struct {
 	TextWithoutAtBot string   `json:"text_without_at_bot"`
 	Title            string   `json:"title"`
 	ImageKeys        []string `json:"image_keys"`
-	ImageHeight      string   `json:""image_height""`
+	ImageHeight      string   `json:"image_height"`
 	ImageWidth       string   `json:"image_width"`
 	ImageKey         string   `json:"image_key"`
 	FileKey          string   `json:"file_key"`
@@ -78,3 +78,82 @@ type MessageRead struct {
 	OpenId         string   `json:"open_id"`
 	OpenMessageIds []string `json:"open_message_ids"`
 }
+
+// MessageReceiveV1 接收消息 (2.0 版本 im.message.receive_v1) https://open.feishu.cn/document/uAjLw4CM/ukTMukTMukTM/reference/im-v1/message/events/receive,
+// 与 Message 不同，它包含完整的消息内容以及 @ 的用户列表，可用 ParseContent 解析消息内容
+type MessageReceiveV1 struct {
+	Sender struct {
+		SenderId   UserId `json:"sender_id"`
+		SenderType string `json:"sender_type"`
+		TenantKey  string `json:"tenant_key"`
+	} `json:"sender"`
+	Message struct {
+		MessageId   string           `json:"message_id"`
+		RootId      string           `json:"root_id"`
+		ParentId    string           `json:"parent_id"`
+		CreateTime  string           `json:"create_time"`
+		ChatId      string           `json:"chat_id"`
+		ChatType    string           `json:"chat_type"`
+		MessageType string           `json:"message_type"`
+		Content     string           `json:"content"`
+		Mentions    []MessageMention `json:"mentions"`
+	} `json:"message"`
+}
+
+// MessageReadV1 消息已读 (2.0 版本 im.message.message_read_v1) https://open.feishu.cn/document/uAjLw4CM/ukTMukTMukTM/reference/im-v1/message/events/message_read
+type MessageReadV1 struct {
+	Reader struct {
+		ReaderId  UserId `json:"reader_id"`
+		ReadTime  string `json:"read_time"`
+		TenantKey string `json:"tenant_key"`
+	} `json:"reader"`
+	MessageIdList []string `json:"message_id_list"`
+}
+
+// MessageReactionCreatedV1 新增消息表情回复 (2.0 版本 im.message.reaction.created_v1) https://open.feishu.cn/document/uAjLw4CM/ukTMukTMukTM/reference/im-v1/message-reaction/events/created
+type MessageReactionCreatedV1 struct {
+	MessageReaction
+}
+
+// MessageReactionDeletedV1 删除消息表情回复 (2.0 版本 im.message.reaction.deleted_v1) https://open.feishu.cn/document/uAjLw4CM/ukTMukTMukTM/reference/im-v1/message-reaction/events/deleted
+type MessageReactionDeletedV1 struct {
+	MessageReaction
+}
+
+// MessageReaction 是表情回复事件的内容
+type MessageReaction struct {
+	MessageId    string `json:"message_id"`
+	ReactionType struct {
+		EmojiType string `json:"emoji_type"`
+	} `json:"reaction_type"`
+	// OperatorType 是操作人类型: user/app
+	OperatorType string `json:"operator_type"`
+	// UserId 是操作人, OperatorType 为 user 时有
+	UserId UserId `json:"user_id"`
+	// AppId 是操作的应用, OperatorType 为 app 时有
+	AppId      string `json:"app_id"`
+	ActionTime string `json:"action_time"`
+}
+
+// MessageMention 是消息中被 @ 的用户, 消息内容中以 Key (如 @_user_1) 占位
+type MessageMention struct {
+	Key       string `json:"key"`
+	Id        UserId `json:"id"`
+	Name      string `json:"name"`
+	TenantKey string `json:"tenant_key"`
+}
+
+// ParseContent 根据消息类型解析消息内容, 见 ParseMessageContent
+func (msg *MessageReceiveV1) ParseContent() (MessageContent, error) {
+	return ParseMessageContent(msg.Message.MessageType, msg.Message.Content)
+}
+
+// IsMentioned 判断 openId 对应的用户 (或机器人) 是否被 @
+func (msg *MessageReceiveV1) IsMentioned(openId string) bool {
+	for _, mention := range msg.Message.Mentions {
+		if mention.Id.OpenId == openId {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,59 @@
+package events
+
+// CalendarChangedV4 日历变更 (2.0 版本 calendar.calendar.changed_v4) https://open.feishu.cn/document/uAjLw4CM/ukTMukTMukTM/reference/calendar-v4/calendar/events/changed
+type CalendarChangedV4 struct {
+	UserIdList []UserId `json:"user_id_list"`
+}
+
+// CalendarAclCreatedV4 日历访问控制创建 (2.0 版本 calendar.calendar.acl.created_v4) https://open.feishu.cn/document/uAjLw4CM/ukTMukTMukTM/reference/calendar-v4/calendar-acl/events/created
+type CalendarAclCreatedV4 struct {
+	AclId      string           `json:"acl_id"`
+	Role       string           `json:"role"`
+	Scope      CalendarAclScope `json:"scope"`
+	UserIdList []UserId         `json:"user_id_list"`
+}
+
+// CalendarAclDeletedV4 日历访问控制移除 (2.0 版本 calendar.calendar.acl.deleted_v4) https://open.feishu.cn/document/uAjLw4CM/ukTMukTMukTM/reference/calendar-v4/calendar-acl/events/deleted
+type CalendarAclDeletedV4 struct {
+	AclId      string           `json:"acl_id"`
+	Role       string           `json:"role"`
+	Scope      CalendarAclScope `json:"scope"`
+	UserIdList []UserId         `json:"user_id_list"`
+}
+
+// CalendarEventChangedV4 日程变更 (2.0 版本 calendar.calendar.event.changed_v4) https://open.feishu.cn/document/uAjLw4CM/ukTMukTMukTM/reference/calendar-v4/calendar-event/events/changed
+type CalendarEventChangedV4 struct {
+	CalendarId string   `json:"calendar_id"`
+	UserIdList []UserId `json:"user_id_list"`
+}
+
+// CalendarAclScope 是日历访问控制的权限生效范围
+type CalendarAclScope struct {
+	// Type 目前只有 user
+	Type   string  `json:"type"`
+	UserId *UserId `json:"user_id"`
+}
+
+// MeetingRoomCreatedV1 会议室创建 (2.0 版本 meeting_room.meeting_room.created_v1) https://open.feishu.cn/document/ukTMukTMukTM/uITOyYjLykjM24iM5IjN
+type MeetingRoomCreatedV1 struct {
+	RoomName string `json:"room_name"`
+	RoomId   string `json:"room_id"`
+}
+
+// MeetingRoomUpdatedV1 会议室更新 (2.0 版本 meeting_room.meeting_room.updated_v1) https://open.feishu.cn/document/ukTMukTMukTM/uITOyYjLykjM24iM5IjN
+type MeetingRoomUpdatedV1 struct {
+	RoomName string `json:"room_name"`
+	RoomId   string `json:"room_id"`
+}
+
+// MeetingRoomDeletedV1 会议室删除 (2.0 版本 meeting_room.meeting_room.deleted_v1) https://open.feishu.cn/document/ukTMukTMukTM/uITOyYjLykjM24iM5IjN
+type MeetingRoomDeletedV1 struct {
+	RoomName string `json:"room_name"`
+	RoomId   string `json:"room_id"`
+}
+
+// MeetingRoomStatusChangedV1 会议室状态信息变更 (2.0 版本 meeting_room.meeting_room.status_changed_v1) https://open.feishu.cn/document/ukTMukTMukTM/uITOyYjLykjM24iM5IjN
+type MeetingRoomStatusChangedV1 struct {
+	RoomName string `json:"room_name"`
+	RoomId   string `json:"room_id"`
+}
@@ -0,0 +1,88 @@
+package events
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCalendarAndVcEvents(t *testing.T) {
+	assert := assert.New(t)
+
+	{
+		ev := decodeEvent(assert, "calendar.calendar.acl.created_v4", `{
+			"acl_id": "user_xxxxxx",
+			"role": "writer",
+			"scope": {
+				"type": "user",
+				"user_id": {
+					"union_id": "on_cad4860e7af114fb4ff6c5d496d1dd76",
+					"user_id": "xxxxxxxxxx",
+					"open_id": "ou_7d8a6e6df7621556ce0d21922b676706ccs"
+				}
+			},
+			"user_id_list": [
+				{"open_id": "ou_7d8a6e6df7621556ce0d21922b676706ccs"}
+			]
+		}`).(*CalendarAclCreatedV4)
+		assert.Equal("writer", ev.Role)
+		if assert.NotNil(ev.Scope.UserId) {
+			assert.Equal("xxxxxxxxxx", ev.Scope.UserId.UserId)
+		}
+		assert.Len(ev.UserIdList, 1)
+	}
+
+	{
+		ev := decodeEvent(assert, "calendar.calendar.event.changed_v4", `{
+			"calendar_id": "feishu.cn_xxxxxxxxxx@group.calendar.feishu.cn",
+			"user_id_list": [
+				{"union_id": "on_1", "user_id": "u1", "open_id": "ou_1"},
+				{"union_id": "on_2", "user_id": "u2", "open_id": "ou_2"}
+			]
+		}`).(*CalendarEventChangedV4)
+		assert.Equal("feishu.cn_xxxxxxxxxx@group.calendar.feishu.cn", ev.CalendarId)
+		assert.Equal("u2", ev.UserIdList[1].UserId)
+	}
+
+	{
+		ev := decodeEvent(assert, "meeting_room.meeting_room.created_v1", `{
+			"room_name": "F2-会议室",
+			"room_id": "omm_eada1d61a550955240c28757e7dec3af"
+		}`).(*MeetingRoomCreatedV1)
+		assert.Equal("omm_eada1d61a550955240c28757e7dec3af", ev.RoomId)
+	}
+
+	{
+		ev := decodeEvent(assert, "vc.meeting.leave_meeting_v1", `{
+			"meeting": {
+				"id": "6911188411934433028",
+				"topic": "my meeting",
+				"meeting_no": "235812466",
+				"start_time": "1608883322",
+				"end_time": "1608883899",
+				"host_user": {
+					"id": {"user_id": "host"},
+					"user_role": 2,
+					"user_type": 1
+				},
+				"owner": {
+					"id": {"user_id": "owner"},
+					"user_role": 1,
+					"user_type": 1
+				}
+			},
+			"operator": {
+				"id": {"union_id": "on_1", "user_id": "u1", "open_id": "ou_1"},
+				"user_role": 1,
+				"user_type": 1
+			},
+			"leave_reason": 1
+		}`).(*LeaveMeetingV1)
+		assert.Equal("235812466", ev.Meeting.MeetingNo)
+		assert.Equal("host", ev.Meeting.HostUser.Id.UserId)
+		assert.Equal(2, ev.Meeting.HostUser.UserRole)
+		assert.Equal("ou_1", ev.Operator.Id.OpenId)
+		assert.Equal(1, ev.LeaveReason)
+	}
+
+}
@@ -0,0 +1,165 @@
+package events
+
+// ChatDisband 群解散 https://open.feishu.cn/document/ukTMukTMukTM/uQDOwUjL0gDM14CN4ATN/event/group-closed
+type ChatDisband struct {
+	AppId     string       `json:"app_id"`
+	TenantKey string       `json:"tenant_key"`
+	ChatId    string       `json:"chat_id"`
+	Operator  ChatOperator `json:"operator"`
+}
+
+// GroupSettingUpdate 群配置修改 https://open.feishu.cn/document/ukTMukTMukTM/uQDOwUjL0gDM14CN4ATN/event/group-configuration-changes
+type GroupSettingUpdate struct {
+	AppId        string       `json:"app_id"`
+	TenantKey    string       `json:"tenant_key"`
+	ChatId       string       `json:"chat_id"`
+	Operator     ChatOperator `json:"operator"`
+	BeforeChange GroupSetting `json:"before_change"`
+	AfterChange  GroupSetting `json:"after_change"`
+}
+
+// AddUserToChat 用户进群 https://open.feishu.cn/document/ukTMukTMukTM/uQDOwUjL0gDM14CN4ATN/event/user-in-and-out-of-group
+type AddUserToChat struct {
+	AppId     string       `json:"app_id"`
+	TenantKey string       `json:"tenant_key"`
+	ChatId    string       `json:"chat_id"`
+	Operator  ChatOperator `json:"operator"`
+	Users     []ChatUser   `json:"users"`
+}
+
+// RemoveUserFromChat 用户出群 https://open.feishu.cn/document/ukTMukTMukTM/uQDOwUjL0gDM14CN4ATN/event/user-in-and-out-of-group
+type RemoveUserFromChat struct {
+	AppId     string       `json:"app_id"`
+	TenantKey string       `json:"tenant_key"`
+	ChatId    string       `json:"chat_id"`
+	Operator  ChatOperator `json:"operator"`
+	Users     []ChatUser   `json:"users"`
+}
+
+// RevokeAddUserFromChat 撤销拉用户进群 https://open.feishu.cn/document/ukTMukTMukTM/uQDOwUjL0gDM14CN4ATN/event/user-in-and-out-of-group
+type RevokeAddUserFromChat struct {
+	AppId     string       `json:"app_id"`
+	TenantKey string       `json:"tenant_key"`
+	ChatId    string       `json:"chat_id"`
+	Operator  ChatOperator `json:"operator"`
+	Users     []ChatUser   `json:"users"`
+}
+
+// ChatOperator 是 1.0 版本群事件中的操作者
+type ChatOperator struct {
+	OpenId string `json:"open_id"`
+	UserId string `json:"user_id"`
+}
+
+// ChatUser 是 1.0 版本群事件中的用户
+type ChatUser struct {
+	Name   string `json:"name"`
+	OpenId string `json:"open_id"`
+	UserId string `json:"user_id"`
+}
+
+// GroupSetting 是 1.0 版本群配置修改事件中的群配置
+type GroupSetting struct {
+	OwnerOpenId         string `json:"owner_open_id"`
+	OwnerUserId         string `json:"owner_user_id"`
+	AddMemberPermission string `json:"add_member_permission"`
+	MessageNotification bool   `json:"message_notification"`
+}
+
+// ChatDisbandedV1 群解散 (2.0 版本 im.chat.disbanded_v1) https://open.feishu.cn/document/uAjLw4CM/ukTMukTMukTM/reference/im-v1/chat/events/disbanded
+type ChatDisbandedV1 struct {
+	ChatId            string `json:"chat_id"`
+	OperatorId        UserId `json:"operator_id"`
+	External          bool   `json:"external"`
+	OperatorTenantKey string `json:"operator_tenant_key"`
+}
+
+// ChatUpdatedV1 群配置修改 (2.0 版本 im.chat.updated_v1) https://open.feishu.cn/document/uAjLw4CM/ukTMukTMukTM/reference/im-v1/chat/events/updated
+type ChatUpdatedV1 struct {
+	ChatId            string         `json:"chat_id"`
+	OperatorId        UserId         `json:"operator_id"`
+	External          bool           `json:"external"`
+	OperatorTenantKey string         `json:"operator_tenant_key"`
+	BeforeChange      ChatSettingV1  `json:"before_change"`
+	AfterChange       ChatSettingV1  `json:"after_change"`
+	ModeratorList     *ChatModerator `json:"moderator_list"`
+}
+
+// ChatMemberUserAddedV1 用户进群 (2.0 版本 im.chat.member.user.added_v1) https://open.feishu.cn/document/uAjLw4CM/ukTMukTMukTM/reference/im-v1/chat-member/events/added
+type ChatMemberUserAddedV1 struct {
+	ChatId            string         `json:"chat_id"`
+	OperatorId        UserId         `json:"operator_id"`
+	External          bool           `json:"external"`
+	OperatorTenantKey string         `json:"operator_tenant_key"`
+	Users             []ChatMemberV1 `json:"users"`
+}
+
+// ChatMemberUserDeletedV1 用户出群 (2.0 版本 im.chat.member.user.deleted_v1) https://open.feishu.cn/document/uAjLw4CM/ukTMukTMukTM/reference/im-v1/chat-member/events/deleted
+type ChatMemberUserDeletedV1 struct {
+	ChatId            string         `json:"chat_id"`
+	OperatorId        UserId         `json:"operator_id"`
+	External          bool           `json:"external"`
+	OperatorTenantKey string         `json:"operator_tenant_key"`
+	Users             []ChatMemberV1 `json:"users"`
+}
+
+// ChatMemberUserWithdrawnV1 撤销拉用户进群 (2.0 版本 im.chat.member.user.withdrawn_v1) https://open.feishu.cn/document/uAjLw4CM/ukTMukTMukTM/reference/im-v1/chat-member/events/withdrawn
+type ChatMemberUserWithdrawnV1 struct {
+	ChatId            string         `json:"chat_id"`
+	OperatorId        UserId         `json:"operator_id"`
+	External          bool           `json:"external"`
+	OperatorTenantKey string         `json:"operator_tenant_key"`
+	Users             []ChatMemberV1 `json:"users"`
+}
+
+// ChatMemberBotAddedV1 机器人进群 (2.0 版本 im.chat.member.bot.added_v1) https://open.feishu.cn/document/uAjLw4CM/ukTMukTMukTM/reference/im-v1/chat-member-bot/events/added
+type ChatMemberBotAddedV1 struct {
+	ChatId            string `json:"chat_id"`
+	OperatorId        UserId `json:"operator_id"`
+	External          bool   `json:"external"`
+	OperatorTenantKey string `json:"operator_tenant_key"`
+}
+
+// ChatMemberBotDeletedV1 机器人被移出群 (2.0 版本 im.chat.member.bot.deleted_v1) https://open.feishu.cn/document/uAjLw4CM/ukTMukTMukTM/reference/im-v1/chat-member-bot/events/deleted
+type ChatMemberBotDeletedV1 struct {
+	ChatId            string `json:"chat_id"`
+	OperatorId        UserId `json:"operator_id"`
+	External          bool   `json:"external"`
+	OperatorTenantKey string `json:"operator_tenant_key"`
+}
+
+// ChatMemberV1 是 2.0 版本群成员事件中的用户
+type ChatMemberV1 struct {
+	Name      string `json:"name"`
+	TenantKey string `json:"tenant_key"`
+	UserId    UserId `json:"user_id"`
+}
+
+// ChatSettingV1 是 2.0 版本群配置修改事件中的群配置，只包含有变化的字段
+type ChatSettingV1 struct {
+	Avatar                 string            `json:"avatar"`
+	Name                   string            `json:"name"`
+	Description            string            `json:"description"`
+	I18nNames              map[string]string `json:"i18n_names"`
+	AddMemberPermission    string            `json:"add_member_permission"`
+	ShareCardPermission    string            `json:"share_card_permission"`
+	AtAllPermission        string            `json:"at_all_permission"`
+	EditPermission         string            `json:"edit_permission"`
+	MembershipApproval     string            `json:"membership_approval"`
+	JoinMessageVisibility  string            `json:"join_message_visibility"`
+	LeaveMessageVisibility string            `json:"leave_message_visibility"`
+	ModerationPermission   string            `json:"moderation_permission"`
+	OwnerId                *UserId           `json:"owner_id"`
+}
+
+// ChatModerator 是 2.0 版本群配置修改事件中的发言权限变更
+type ChatModerator struct {
+	AddedMemberList []struct {
+		TenantKey string `json:"tenant_key"`
+		UserId    UserId `json:"user_id"`
+	} `json:"added_member_list"`
+	RemovedMemberList []struct {
+		TenantKey string `json:"tenant_key"`
+		UserId    UserId `json:"user_id"`
+	} `json:"removed_member_list"`
+}
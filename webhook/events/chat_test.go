@@ -0,0 +1,117 @@
+package events
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestChatEvents(t *testing.T) {
+	assert := assert.New(t)
+
+	{
+		ev := decodeEvent(assert, "group_setting_update", `{
+			"app_id": "cli_9c8609450f78d102",
+			"chat_id": "oc_9e9619b938c9571c1c3165681cdaead5",
+			"operator": {
+				"open_id": "ou_18eac85d35a26f989317ad4f02e8bbbb",
+				"user_id": "ca51d83b"
+			},
+			"tenant_key": "736588c9260f175d",
+			"type": "group_setting_update",
+			"before_change": {
+				"owner_open_id": "ou_18eac85d35a26f989317ad4f02e8bbbb",
+				"owner_user_id": "ca51d83b",
+				"add_member_permission": "allowed",
+				"message_notification": false
+			},
+			"after_change": {
+				"owner_open_id": "ou_18eac85d35a26f989317ad4f02e8cccc",
+				"owner_user_id": "ca51d83c",
+				"add_member_permission": "owner",
+				"message_notification": true
+			}
+		}`).(*GroupSettingUpdate)
+		assert.Equal("ca51d83b", ev.Operator.UserId)
+		assert.Equal("allowed", ev.BeforeChange.AddMemberPermission)
+		assert.Equal("owner", ev.AfterChange.AddMemberPermission)
+		assert.True(ev.AfterChange.MessageNotification)
+	}
+
+	{
+		ev := decodeEvent(assert, "add_user_to_chat", `{
+			"app_id": "cli_9c8609450f78d102",
+			"chat_id": "oc_9e9619b938c9571c1c3165681cdaead5",
+			"operator": {
+				"open_id": "ou_18eac85d35a26f989317ad4f02e8bbbb",
+				"user_id": "ca51d83b"
+			},
+			"tenant_key": "736588c9260f175d",
+			"type": "add_user_to_chat",
+			"users": [
+				{"name": "James", "open_id": "ou_706adeb944ab1473b9fb3e7da2a40b68", "user_id": "51g97a4g"},
+				{"name": "Lily", "open_id": "ou_7481aa7fb8c2e15ad6fe4a4e4fd55a7c", "user_id": "6e125a0b"}
+			]
+		}`).(*AddUserToChat)
+		if assert.Len(ev.Users, 2) {
+			assert.Equal("Lily", ev.Users[1].Name)
+		}
+	}
+
+	{
+		ev := decodeEvent(assert, "im.chat.updated_v1", `{
+			"chat_id": "oc_a0553eda9014c201e6969b478895c230",
+			"operator_id": {
+				"union_id": "on_8ed6aa67826108097d9ee143816345",
+				"user_id": "e33ggbyz",
+				"open_id": "ou_84aad35d084aa403a838cf73ee18467"
+			},
+			"external": false,
+			"operator_tenant_key": "86gb3b7a3d3f",
+			"after_change": {
+				"name": "测试群 2",
+				"owner_id": {"open_id": "ou_84aad35d084aa403a838cf73ee18467"}
+			},
+			"before_change": {
+				"name": "测试群"
+			},
+			"moderator_list": {
+				"added_member_list": [
+					{"tenant_key": "86gb3b7a3d3f", "user_id": {"open_id": "ou_1"}}
+				]
+			}
+		}`).(*ChatUpdatedV1)
+		assert.Equal("e33ggbyz", ev.OperatorId.UserId)
+		assert.Equal("测试群", ev.BeforeChange.Name)
+		assert.Equal("测试群 2", ev.AfterChange.Name)
+		assert.Nil(ev.BeforeChange.OwnerId)
+		if assert.NotNil(ev.AfterChange.OwnerId) {
+			assert.Equal("ou_84aad35d084aa403a838cf73ee18467", ev.AfterChange.OwnerId.OpenId)
+		}
+		assert.Equal("ou_1", ev.ModeratorList.AddedMemberList[0].UserId.OpenId)
+	}
+
+	{
+		ev := decodeEvent(assert, "im.chat.member.user.deleted_v1", `{
+			"chat_id": "oc_413871369cb3bc3e71ead2bc7eb3c2d4",
+			"operator_id": {"open_id": "ou_84aad35d084aa403a838cf73ee18467"},
+			"external": false,
+			"operator_tenant_key": "86gb3b7a3d3f",
+			"users": [
+				{
+					"name": "James",
+					"tenant_key": "86gb3b7a3d3f",
+					"user_id": {
+						"union_id": "on_8ed6aa67826108097d9ee143816345",
+						"user_id": "e33ggbyz",
+						"open_id": "ou_84aad35d084aa403a838cf73ee18467"
+					}
+				}
+			]
+		}`).(*ChatMemberUserDeletedV1)
+		if assert.Len(ev.Users, 1) {
+			assert.Equal("e33ggbyz", ev.Users[0].UserId.UserId)
+		}
+	}
+
+}
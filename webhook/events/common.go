@@ -0,0 +1,8 @@
+package events
+
+// UserId 是 2.0 版本事件中的用户 ID 集合
+type UserId struct {
+	UnionId string `json:"union_id"`
+	UserId  string `json:"user_id"`
+	OpenId  string `json:"open_id"`
+}
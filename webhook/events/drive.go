@@ -0,0 +1,135 @@
+package events
+
+import (
+	"encoding/json"
+)
+
+// FileEditV1 文件编辑 (2.0 版本 drive.file.edit_v1) https://open.feishu.cn/document/uAjLw4CM/ukTMukTMukTM/reference/drive-v1/file/events/edit
+type FileEditV1 struct {
+	FileToken        string   `json:"file_token"`
+	FileType         string   `json:"file_type"`
+	SheetId          string   `json:"sheet_id"`
+	OperatorIdList   []UserId `json:"operator_id_list"`
+	SubscriberIdList []UserId `json:"subscriber_id_list"`
+}
+
+// FileReadV1 文件被查看 (2.0 版本 drive.file.read_v1) https://open.feishu.cn/document/uAjLw4CM/ukTMukTMukTM/reference/drive-v1/file/events/read
+type FileReadV1 struct {
+	FileToken        string   `json:"file_token"`
+	FileType         string   `json:"file_type"`
+	OperatorIdList   []UserId `json:"operator_id_list"`
+	SubscriberIdList []UserId `json:"subscriber_id_list"`
+}
+
+// FileTitleUpdatedV1 文件标题变更 (2.0 版本 drive.file.title_updated_v1) https://open.feishu.cn/document/uAjLw4CM/ukTMukTMukTM/reference/drive-v1/file/events/title_updated
+type FileTitleUpdatedV1 struct {
+	FileToken        string   `json:"file_token"`
+	FileType         string   `json:"file_type"`
+	OperatorId       UserId   `json:"operator_id"`
+	SubscriberIdList []UserId `json:"subscriber_id_list"`
+}
+
+// FilePermissionMemberAddedV1 文件协作者添加 (2.0 版本 drive.file.permission_member_added_v1) https://open.feishu.cn/document/uAjLw4CM/ukTMukTMukTM/reference/drive-v1/file/events/permission_member_added
+type FilePermissionMemberAddedV1 struct {
+	FileToken            string   `json:"file_token"`
+	FileType             string   `json:"file_type"`
+	OperatorId           UserId   `json:"operator_id"`
+	UserList             []UserId `json:"user_list"`
+	ChatList             []string `json:"chat_list"`
+	OpenDepartmentIdList []string `json:"open_department_id_list"`
+	SubscriberIdList     []UserId `json:"subscriber_id_list"`
+}
+
+// FilePermissionMemberRemovedV1 文件协作者移除 (2.0 版本 drive.file.permission_member_removed_v1) https://open.feishu.cn/document/uAjLw4CM/ukTMukTMukTM/reference/drive-v1/file/events/permission_member_removed
+type FilePermissionMemberRemovedV1 struct {
+	FileToken            string   `json:"file_token"`
+	FileType             string   `json:"file_type"`
+	OperatorId           UserId   `json:"operator_id"`
+	UserList             []UserId `json:"user_list"`
+	ChatList             []string `json:"chat_list"`
+	OpenDepartmentIdList []string `json:"open_department_id_list"`
+	SubscriberIdList     []UserId `json:"subscriber_id_list"`
+}
+
+// FileTrashedV1 文件被删除到回收站 (2.0 版本 drive.file.trashed_v1) https://open.feishu.cn/document/uAjLw4CM/ukTMukTMukTM/reference/drive-v1/file/events/trashed
+type FileTrashedV1 struct {
+	FileToken        string   `json:"file_token"`
+	FileType         string   `json:"file_type"`
+	OperatorId       UserId   `json:"operator_id"`
+	SubscriberIdList []UserId `json:"subscriber_id_list"`
+}
+
+// FileDeletedV1 文件彻底删除 (2.0 版本 drive.file.deleted_v1) https://open.feishu.cn/document/uAjLw4CM/ukTMukTMukTM/reference/drive-v1/file/events/deleted
+type FileDeletedV1 struct {
+	FileToken        string   `json:"file_token"`
+	FileType         string   `json:"file_type"`
+	OperatorId       UserId   `json:"operator_id"`
+	SubscriberIdList []UserId `json:"subscriber_id_list"`
+}
+
+// BitableRecordChangedV1 多维表格记录变更 (2.0 版本 drive.file.bitable_record_changed_v1) https://open.feishu.cn/document/uAjLw4CM/ukTMukTMukTM/reference/drive-v1/file/events/bitable_record_changed
+type BitableRecordChangedV1 struct {
+	FileToken        string                `json:"file_token"`
+	FileType         string                `json:"file_type"`
+	TableId          string                `json:"table_id"`
+	Revision         int64                 `json:"revision"`
+	OperatorId       UserId                `json:"operator_id"`
+	ActionList       []BitableRecordAction `json:"action_list"`
+	SubscriberIdList []UserId              `json:"subscriber_id_list"`
+	UpdateTime       int64                 `json:"update_time"`
+}
+
+// BitableRecordAction 是多维表格记录变更事件中的单个变更
+type BitableRecordAction struct {
+	RecordId string `json:"record_id"`
+
+	// Action 是变更类型: record_added/record_deleted/record_edited
+	Action string `json:"action"`
+
+	BeforeValue []BitableFieldValue `json:"before_value"`
+	AfterValue  []BitableFieldValue `json:"after_value"`
+}
+
+// BitableFieldValue 是多维表格记录中某个字段的值
+type BitableFieldValue struct {
+	FieldId string `json:"field_id"`
+
+	// FieldValue 是 json 编码后的字段值, 可用 DecodeFieldValue 解析
+	FieldValue string `json:"field_value"`
+}
+
+// BitableFieldChangedV1 多维表格字段变更 (2.0 版本 drive.file.bitable_field_changed_v1) https://open.feishu.cn/document/uAjLw4CM/ukTMukTMukTM/reference/drive-v1/file/events/bitable_field_changed
+type BitableFieldChangedV1 struct {
+	FileToken        string               `json:"file_token"`
+	FileType         string               `json:"file_type"`
+	TableId          string               `json:"table_id"`
+	Revision         int64                `json:"revision"`
+	OperatorId       UserId               `json:"operator_id"`
+	ActionList       []BitableFieldAction `json:"action_list"`
+	SubscriberIdList []UserId             `json:"subscriber_id_list"`
+	UpdateTime       int64                `json:"update_time"`
+}
+
+// BitableFieldAction 是多维表格字段变更事件中的单个变更
+type BitableFieldAction struct {
+	// Action 是变更类型: field_added/field_deleted/field_edited
+	Action  string `json:"action"`
+	FieldId string `json:"field_id"`
+
+	BeforeValue *BitableField `json:"before_value"`
+	AfterValue  *BitableField `json:"after_value"`
+}
+
+// BitableField 是多维表格的字段定义
+type BitableField struct {
+	Id          string          `json:"id"`
+	Name        string          `json:"name"`
+	Type        int             `json:"type"`
+	Description string          `json:"description"`
+	Property    json.RawMessage `json:"property"`
+}
+
+// DecodeFieldValue 将 FieldValue 解析到 v 中
+func (value *BitableFieldValue) DecodeFieldValue(v interface{}) error {
+	return json.Unmarshal([]byte(value.FieldValue), v)
+}
@@ -0,0 +1,98 @@
+package events
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDriveEvents(t *testing.T) {
+	assert := assert.New(t)
+
+	{
+		ev := decodeEvent(assert, "drive.file.permission_member_added_v1", `{
+			"file_token": "doccnxxxxxx",
+			"file_type": "doc",
+			"operator_id": {"open_id": "ou_1", "union_id": "on_1", "user_id": "u1"},
+			"user_list": [{"open_id": "ou_2", "union_id": "on_2", "user_id": "u2"}],
+			"chat_list": ["oc_1"],
+			"open_department_id_list": ["od_1"],
+			"subscriber_id_list": [{"open_id": "ou_1"}]
+		}`).(*FilePermissionMemberAddedV1)
+		assert.Equal("doc", ev.FileType)
+		assert.Equal("u2", ev.UserList[0].UserId)
+		assert.Equal([]string{"oc_1"}, ev.ChatList)
+		assert.Equal([]string{"od_1"}, ev.OpenDepartmentIdList)
+	}
+
+	{
+		ev := decodeEvent(assert, "drive.file.bitable_record_changed_v1", `{
+			"file_type": "bitable",
+			"file_token": "bascnxxxxxx",
+			"table_id": "tblxxxxxx",
+			"revision": 10,
+			"operator_id": {"open_id": "ou_1", "union_id": "on_1", "user_id": "u1"},
+			"action_list": [
+				{
+					"record_id": "recxxxxxx",
+					"action": "record_edited",
+					"before_value": [
+						{"field_id": "fld1", "field_value": "\"old\""}
+					],
+					"after_value": [
+						{"field_id": "fld1", "field_value": "\"new\""},
+						{"field_id": "fld2", "field_value": "[{\"text\":\"a\",\"type\":\"text\"}]"}
+					]
+				}
+			],
+			"subscriber_id_list": [{"open_id": "ou_1"}],
+			"update_time": 1661853251
+		}`).(*BitableRecordChangedV1)
+		assert.Equal("tblxxxxxx", ev.TableId)
+		assert.Equal(int64(10), ev.Revision)
+		if assert.Len(ev.ActionList, 1) {
+			action := ev.ActionList[0]
+			assert.Equal("record_edited", action.Action)
+
+			var before, after string
+			assert.NoError(action.BeforeValue[0].DecodeFieldValue(&before))
+			assert.NoError(action.AfterValue[0].DecodeFieldValue(&after))
+			assert.Equal("old", before)
+			assert.Equal("new", after)
+
+			var texts []map[string]string
+			assert.NoError(action.AfterValue[1].DecodeFieldValue(&texts))
+			assert.Equal("a", texts[0]["text"])
+		}
+	}
+
+	{
+		ev := decodeEvent(assert, "drive.file.bitable_field_changed_v1", `{
+			"file_type": "bitable",
+			"file_token": "bascnxxxxxx",
+			"table_id": "tblxxxxxx",
+			"revision": 11,
+			"operator_id": {"open_id": "ou_1"},
+			"action_list": [
+				{
+					"action": "field_added",
+					"field_id": "fld3",
+					"after_value": {
+						"id": "fld3",
+						"name": "状态",
+						"type": 3,
+						"description": "",
+						"property": {"options": [{"name": "done", "id": "opt1"}]}
+					}
+				}
+			],
+			"update_time": 1661853252
+		}`).(*BitableFieldChangedV1)
+		if assert.Len(ev.ActionList, 1) {
+			assert.Nil(ev.ActionList[0].BeforeValue)
+			assert.Equal("状态", ev.ActionList[0].AfterValue.Name)
+			assert.Equal(3, ev.ActionList[0].AfterValue.Type)
+		}
+	}
+
+}
@@ -0,0 +1,491 @@
+package events
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// MessageContent 是解析后的消息内容，具体类型由消息类型 (msg_type) 决定:
+//
+//	text          -> *TextContent
+//	post          -> *PostContent
+//	image         -> *ImageContent
+//	file          -> *FileContent
+//	audio         -> *AudioContent
+//	media         -> *MediaContent
+//	sticker       -> *StickerContent
+//	share_chat    -> *ShareChatContent
+//	share_user    -> *ShareUserContent
+//	interactive   -> *InteractiveContent
+//	merge_forward -> *MergeForwardContent
+//	其它          -> *UnknownContent
+type MessageContent interface {
+	// MessageContentType 返回消息类型
+	MessageContentType() string
+}
+
+// TextContent 是文本消息内容, Text 中被 @ 的用户以 @_user_1 这样的 key 占位
+type TextContent struct {
+	Text string `json:"text"`
+}
+
+// PostContent 是富文本消息内容
+type PostContent struct {
+	Title      string          `json:"title"`
+	Paragraphs []PostParagraph `json:"content"`
+}
+
+// PostParagraph 是富文本中的一个段落, 由若干行内元素组成
+type PostParagraph []PostElement
+
+// PostElement 是富文本中的行内元素, 具体类型由 tag 决定:
+//
+//	text       -> *PostText
+//	a          -> *PostLink
+//	at         -> *PostAt
+//	img        -> *PostImage
+//	media      -> *PostMedia
+//	emotion    -> *PostEmotion
+//	code_block -> *PostCodeBlock
+//	hr         -> *PostHr
+//	其它       -> *PostUnknown
+type PostElement interface {
+	// PostTag 返回元素的 tag
+	PostTag() string
+}
+
+// PostText 是富文本中的文本
+type PostText struct {
+	Text     string   `json:"text"`
+	UnEscape bool     `json:"un_escape"`
+	Style    []string `json:"style"`
+}
+
+// PostLink 是富文本中的超链接
+type PostLink struct {
+	Text  string   `json:"text"`
+	Href  string   `json:"href"`
+	Style []string `json:"style"`
+}
+
+// PostAt 是富文本中的 @, UserId 为 @_user_1 这样的 key (接收消息时) 或 open_id, all 表示 @所有人
+type PostAt struct {
+	UserId   string   `json:"user_id"`
+	UserName string   `json:"user_name"`
+	Style    []string `json:"style"`
+}
+
+// PostImage 是富文本中的图片
+type PostImage struct {
+	ImageKey string `json:"image_key"`
+	Width    int    `json:"width"`
+	Height   int    `json:"height"`
+}
+
+// PostMedia 是富文本中的视频
+type PostMedia struct {
+	FileKey  string `json:"file_key"`
+	ImageKey string `json:"image_key"`
+}
+
+// PostEmotion 是富文本中的表情
+type PostEmotion struct {
+	EmojiType string `json:"emoji_type"`
+}
+
+// PostCodeBlock 是富文本中的代码块
+type PostCodeBlock struct {
+	Language string `json:"language"`
+	Text     string `json:"text"`
+}
+
+// PostHr 是富文本中的分割线
+type PostHr struct{}
+
+// PostUnknown 是富文本中未知 tag 的元素
+type PostUnknown struct {
+	Tag string
+	Raw json.RawMessage
+}
+
+// ImageContent 是图片消息内容
+type ImageContent struct {
+	ImageKey string `json:"image_key"`
+}
+
+// FileContent 是文件消息内容
+type FileContent struct {
+	FileKey  string `json:"file_key"`
+	FileName string `json:"file_name"`
+}
+
+// AudioContent 是语音消息内容, Duration 单位为毫秒
+type AudioContent struct {
+	FileKey  string `json:"file_key"`
+	Duration int    `json:"duration"`
+}
+
+// MediaContent 是视频消息内容, Duration 单位为毫秒
+type MediaContent struct {
+	FileKey  string `json:"file_key"`
+	ImageKey string `json:"image_key"`
+	FileName string `json:"file_name"`
+	Duration int    `json:"duration"`
+}
+
+// StickerContent 是表情包消息内容
+type StickerContent struct {
+	FileKey string `json:"file_key"`
+}
+
+// ShareChatContent 是群名片消息内容
+type ShareChatContent struct {
+	ChatId string `json:"chat_id"`
+}
+
+// ShareUserContent 是个人名片消息内容
+type ShareUserContent struct {
+	UserId string `json:"user_id"`
+}
+
+// InteractiveContent 是卡片消息内容, Raw 是卡片的 json
+type InteractiveContent struct {
+	Raw json.RawMessage
+}
+
+// MergeForwardContent 是合并转发消息内容, 子消息需要通过接口另外获取
+type MergeForwardContent struct {
+	Content string `json:"content"`
+}
+
+// UnknownContent 是未知类型的消息内容
+type UnknownContent struct {
+	MsgType string
+	Raw     json.RawMessage
+}
+
+// ParseMessageContent 根据消息类型 msgType 解析 json 编码的消息内容 content
+func ParseMessageContent(msgType, content string) (MessageContent, error) {
+	var c MessageContent
+	switch msgType {
+	case "text":
+		c = new(TextContent)
+	case "post":
+		c = new(PostContent)
+	case "image":
+		c = new(ImageContent)
+	case "file":
+		c = new(FileContent)
+	case "audio":
+		c = new(AudioContent)
+	case "media":
+		c = new(MediaContent)
+	case "sticker":
+		c = new(StickerContent)
+	case "share_chat":
+		c = new(ShareChatContent)
+	case "share_user":
+		c = new(ShareUserContent)
+	case "merge_forward":
+		c = new(MergeForwardContent)
+	case "interactive":
+		if !json.Valid([]byte(content)) {
+			return nil, fmt.Errorf("Invalid interactive content")
+		}
+		return &InteractiveContent{Raw: json.RawMessage(content)}, nil
+	default:
+		if !json.Valid([]byte(content)) {
+			return nil, fmt.Errorf("Invalid %s content", msgType)
+		}
+		return &UnknownContent{MsgType: msgType, Raw: json.RawMessage(content)}, nil
+	}
+
+	if err := json.Unmarshal([]byte(content), c); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+// UnmarshalJSON 解析富文本, 同时支持 {"title": ..., "content": ...} 以及按语言区分的
+// {"zh_cn": {"title": ..., "content": ...}} 两种格式，后者优先取 zh_cn，其次 en_us，再次任一语言
+func (c *PostContent) UnmarshalJSON(data []byte) error {
+	type post struct {
+		Title      string              `json:"title"`
+		Paragraphs [][]json.RawMessage `json:"content"`
+	}
+
+	fields := map[string]json.RawMessage{}
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return err
+	}
+
+	// 按语言区分的格式: 所有的值都是对象 (不带语言的格式中 title 是字符串, content 是数组)
+	if isLocaleKeyedPost(fields) {
+		locales := make([]string, 0, len(fields))
+		for locale := range fields {
+			locales = append(locales, locale)
+		}
+		sort.Strings(locales)
+		for _, locale := range []string{"en_us", "zh_cn"} {
+			if _, ok := fields[locale]; ok {
+				locales = append([]string{locale}, locales...)
+			}
+		}
+		if len(locales) == 0 {
+			return nil
+		}
+		data = fields[locales[0]]
+	}
+
+	p := &post{}
+	if err := json.Unmarshal(data, p); err != nil {
+		return err
+	}
+
+	c.Title = p.Title
+	c.Paragraphs = make([]PostParagraph, 0, len(p.Paragraphs))
+	for _, rawElems := range p.Paragraphs {
+		paragraph := make(PostParagraph, 0, len(rawElems))
+		for _, rawElem := range rawElems {
+			elem, err := parsePostElement(rawElem)
+			if err != nil {
+				return err
+			}
+			paragraph = append(paragraph, elem)
+		}
+		c.Paragraphs = append(c.Paragraphs, paragraph)
+	}
+	return nil
+}
+
+func isLocaleKeyedPost(fields map[string]json.RawMessage) bool {
+	if len(fields) == 0 {
+		return false
+	}
+	for _, v := range fields {
+		v = bytes.TrimSpace(v)
+		if len(v) == 0 || v[0] != '{' {
+			return false
+		}
+	}
+	return true
+}
+
+func parsePostElement(raw json.RawMessage) (PostElement, error) {
+	tag := &struct {
+		Tag string `json:"tag"`
+	}{}
+	if err := json.Unmarshal(raw, tag); err != nil {
+		return nil, err
+	}
+
+	var elem PostElement
+	switch tag.Tag {
+	case "text":
+		elem = new(PostText)
+	case "a":
+		elem = new(PostLink)
+	case "at":
+		elem = new(PostAt)
+	case "img":
+		elem = new(PostImage)
+	case "media":
+		elem = new(PostMedia)
+	case "emotion":
+		elem = new(PostEmotion)
+	case "code_block":
+		elem = new(PostCodeBlock)
+	case "hr":
+		return new(PostHr), nil
+	default:
+		return &PostUnknown{Tag: tag.Tag, Raw: raw}, nil
+	}
+
+	if err := json.Unmarshal(raw, elem); err != nil {
+		return nil, err
+	}
+	return elem, nil
+}
+
+// PlainText 返回富文本中的纯文本 (不包括标题)，段落之间以换行分隔，@ 以 "@用户名" 表示
+func (c *PostContent) PlainText() string {
+	lines := make([]string, 0, len(c.Paragraphs))
+	for _, paragraph := range c.Paragraphs {
+		b := &strings.Builder{}
+		for _, elem := range paragraph {
+			switch e := elem.(type) {
+			case *PostText:
+				b.WriteString(e.Text)
+			case *PostLink:
+				b.WriteString(e.Text)
+			case *PostAt:
+				b.WriteString("@" + e.UserName)
+			case *PostCodeBlock:
+				b.WriteString(e.Text)
+			}
+		}
+		lines = append(lines, b.String())
+	}
+	return strings.Join(lines, "\n")
+}
+
+// Ats 返回富文本中所有的 @
+func (c *PostContent) Ats() []*PostAt {
+	ret := []*PostAt{}
+	for _, paragraph := range c.Paragraphs {
+		for _, elem := range paragraph {
+			if at, ok := elem.(*PostAt); ok {
+				ret = append(ret, at)
+			}
+		}
+	}
+	return ret
+}
+
+// Images 返回富文本中所有的图片
+func (c *PostContent) Images() []*PostImage {
+	ret := []*PostImage{}
+	for _, paragraph := range c.Paragraphs {
+		for _, elem := range paragraph {
+			if img, ok := elem.(*PostImage); ok {
+				ret = append(ret, img)
+			}
+		}
+	}
+	return ret
+}
+
+// TextWithoutMentions 返回去掉所有 @ 占位 key 之后的文本 (并去掉首尾空白)
+func (c *TextContent) TextWithoutMentions(mentions []MessageMention) string {
+	return strings.TrimSpace(replaceMentions(c.Text, mentions, func(MessageMention) string { return "" }))
+}
+
+// TextWithMentionNames 返回将 @ 占位 key 替换为 "@用户名" 之后的文本
+func (c *TextContent) TextWithMentionNames(mentions []MessageMention) string {
+	return replaceMentions(c.Text, mentions, func(mention MessageMention) string { return "@" + mention.Name })
+}
+
+// replaceMentions 一次性替换文本中的 @ 占位 key; 较长的 key 优先匹配, 避免 @_user_1 匹配到 @_user_10 的前缀
+func replaceMentions(text string, mentions []MessageMention, fn func(MessageMention) string) string {
+	sorted := make([]MessageMention, 0, len(mentions))
+	for _, mention := range mentions {
+		if mention.Key != "" {
+			sorted = append(sorted, mention)
+		}
+	}
+	sort.SliceStable(sorted, func(i, j int) bool { return len(sorted[i].Key) > len(sorted[j].Key) })
+
+	oldnew := make([]string, 0, 2*len(sorted))
+	for _, mention := range sorted {
+		oldnew = append(oldnew, mention.Key, fn(mention))
+	}
+	return strings.NewReplacer(oldnew...).Replace(text)
+}
+
+// MessageContentType 返回 text
+func (c *TextContent) MessageContentType() string {
+	return "text"
+}
+
+// MessageContentType 返回 post
+func (c *PostContent) MessageContentType() string {
+	return "post"
+}
+
+// MessageContentType 返回 image
+func (c *ImageContent) MessageContentType() string {
+	return "image"
+}
+
+// MessageContentType 返回 file
+func (c *FileContent) MessageContentType() string {
+	return "file"
+}
+
+// MessageContentType 返回 audio
+func (c *AudioContent) MessageContentType() string {
+	return "audio"
+}
+
+// MessageContentType 返回 media
+func (c *MediaContent) MessageContentType() string {
+	return "media"
+}
+
+// MessageContentType 返回 sticker
+func (c *StickerContent) MessageContentType() string {
+	return "sticker"
+}
+
+// MessageContentType 返回 share_chat
+func (c *ShareChatContent) MessageContentType() string {
+	return "share_chat"
+}
+
+// MessageContentType 返回 share_user
+func (c *ShareUserContent) MessageContentType() string {
+	return "share_user"
+}
+
+// MessageContentType 返回 interactive
+func (c *InteractiveContent) MessageContentType() string {
+	return "interactive"
+}
+
+// MessageContentType 返回 merge_forward
+func (c *MergeForwardContent) MessageContentType() string {
+	return "merge_forward"
+}
+
+// MessageContentType 返回原始的消息类型
+func (c *UnknownContent) MessageContentType() string {
+	return c.MsgType
+}
+
+// PostTag 返回 text
+func (e *PostText) PostTag() string {
+	return "text"
+}
+
+// PostTag 返回 a
+func (e *PostLink) PostTag() string {
+	return "a"
+}
+
+// PostTag 返回 at
+func (e *PostAt) PostTag() string {
+	return "at"
+}
+
+// PostTag 返回 img
+func (e *PostImage) PostTag() string {
+	return "img"
+}
+
+// PostTag 返回 media
+func (e *PostMedia) PostTag() string {
+	return "media"
+}
+
+// PostTag 返回 emotion
+func (e *PostEmotion) PostTag() string {
+	return "emotion"
+}
+
+// PostTag 返回 code_block
+func (e *PostCodeBlock) PostTag() string {
+	return "code_block"
+}
+
+// PostTag 返回 hr
+func (e *PostHr) PostTag() string {
+	return "hr"
+}
+
+// PostTag 返回原始的 tag
+func (e *PostUnknown) PostTag() string {
+	return e.Tag
+}
@@ -0,0 +1,173 @@
+package events
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMessageReceive(t *testing.T) {
+	assert := assert.New(t)
+
+	// 文本消息
+	{
+		ev := decodeEvent(assert, "im.message.receive_v1", `{
+			"sender": {
+				"sender_id": {"union_id": "on_1", "user_id": "u1", "open_id": "ou_1"},
+				"sender_type": "user",
+				"tenant_key": "736588c9260f175e"
+			},
+			"message": {
+				"message_id": "om_5ce6d572455d361153b7cb51da133945",
+				"root_id": "om_5ce6d572455d361153b7cb5xxfsdfsdfdsf",
+				"parent_id": "om_5ce6d572455d361153b7cb5xxfsdfsdfdsf",
+				"create_time": "1609073151345",
+				"chat_id": "oc_5ce6d572455d361153b7xx51da133945",
+				"chat_type": "group",
+				"message_type": "text",
+				"content": "{\"text\":\"@_user_1 /deploy svc prod @_user_2\"}",
+				"mentions": [
+					{"key": "@_user_1", "id": {"open_id": "ou_bot"}, "name": "Bot", "tenant_key": "736588c9260f175e"},
+					{"key": "@_user_2", "id": {"open_id": "ou_2"}, "name": "Tom", "tenant_key": "736588c9260f175e"}
+				]
+			}
+		}`).(*MessageReceiveV1)
+		assert.Equal("u1", ev.Sender.SenderId.UserId)
+		assert.True(ev.IsMentioned("ou_bot"))
+		assert.False(ev.IsMentioned("ou_3"))
+
+		c, err := ev.ParseContent()
+		assert.NoError(err)
+		if assert.IsType(&TextContent{}, c) {
+			text := c.(*TextContent)
+			assert.Equal("/deploy svc prod", text.TextWithoutMentions(ev.Message.Mentions))
+			assert.Equal("@Bot /deploy svc prod @Tom", text.TextWithMentionNames(ev.Message.Mentions))
+		}
+	}
+
+	// 富文本
+	{
+		c, err := ParseMessageContent("post", `{
+			"title": "发布通知",
+			"content": [
+				[
+					{"tag": "text", "text": "版本 ", "style": ["bold"]},
+					{"tag": "a", "text": "v1.2.0", "href": "https://example.com/release"},
+					{"tag": "at", "user_id": "@_user_1", "user_name": "Tom"}
+				],
+				[
+					{"tag": "img", "image_key": "img_v2_1", "width": 300, "height": 200}
+				],
+				[
+					{"tag": "hr"},
+					{"tag": "code_block", "language": "GO", "text": "fmt.Println()"},
+					{"tag": "unknown_tag", "foo": "bar"}
+				]
+			]
+		}`)
+		assert.NoError(err)
+		if assert.IsType(&PostContent{}, c) {
+			post := c.(*PostContent)
+			assert.Equal("发布通知", post.Title)
+			assert.Len(post.Paragraphs, 3)
+			assert.Equal(&PostText{Text: "版本 ", Style: []string{"bold"}}, post.Paragraphs[0][0])
+			assert.Equal(&PostLink{Text: "v1.2.0", Href: "https://example.com/release"}, post.Paragraphs[0][1])
+			assert.Equal([]*PostAt{{UserId: "@_user_1", UserName: "Tom"}}, post.Ats())
+			assert.Equal([]*PostImage{{ImageKey: "img_v2_1", Width: 300, Height: 200}}, post.Images())
+			assert.Equal("hr", post.Paragraphs[2][0].PostTag())
+			assert.Equal("unknown_tag", post.Paragraphs[2][2].PostTag())
+			assert.Equal("版本 v1.2.0@Tom\n\nfmt.Println()", post.PlainText())
+		}
+	}
+
+	// 按语言区分的富文本
+	{
+		c, err := ParseMessageContent("post", `{
+			"en_us": {"title": "Release", "content": [[{"tag": "text", "text": "hello"}]]},
+			"zh_cn": {"title": "发布", "content": [[{"tag": "text", "text": "你好"}]]}
+		}`)
+		assert.NoError(err)
+		assert.Equal("发布", c.(*PostContent).Title)
+	}
+
+	// 超过 10 个 @
+	{
+		mentions := []MessageMention{}
+		texts := []string{}
+		for i := 1; i <= 11; i++ {
+			key := fmt.Sprintf("@_user_%d", i)
+			mentions = append(mentions, MessageMention{Key: key, Name: fmt.Sprintf("U%d", i)})
+			texts = append(texts, key)
+		}
+		text := &TextContent{Text: strings.Join(texts, " ") + " hi"}
+		assert.Equal("hi", text.TextWithoutMentions(mentions))
+		assert.Equal("@U1 @U2 @U3 @U4 @U5 @U6 @U7 @U8 @U9 @U10 @U11 hi", text.TextWithMentionNames(mentions))
+
+		text = &TextContent{Text: "@_user_1 @_user_10 /deploy"}
+		assert.Equal("/deploy", text.TextWithoutMentions(mentions))
+		assert.Equal("@U1 @U10 /deploy", text.TextWithMentionNames(mentions))
+	}
+
+	// 只有标题的富文本
+	{
+		c, err := ParseMessageContent("post", `{"title": "x"}`)
+		assert.NoError(err)
+		assert.Equal("x", c.(*PostContent).Title)
+		assert.Len(c.(*PostContent).Paragraphs, 0)
+
+		c, err = ParseMessageContent("post", `{"zh_cn": {"title": "y"}}`)
+		assert.NoError(err)
+		assert.Equal("y", c.(*PostContent).Title)
+	}
+
+	// 其它类型
+	for _, testCase := range []struct {
+		MsgType  string
+		Content  string
+		Expected MessageContent
+	}{
+		{"image", `{"image_key": "img_1"}`, &ImageContent{ImageKey: "img_1"}},
+		{"file", `{"file_key": "file_1", "file_name": "a.txt"}`, &FileContent{FileKey: "file_1", FileName: "a.txt"}},
+		{"audio", `{"file_key": "file_1", "duration": 2000}`, &AudioContent{FileKey: "file_1", Duration: 2000}},
+		{"media", `{"file_key": "file_1", "image_key": "img_1", "file_name": "a.mp4", "duration": 2000}`, &MediaContent{FileKey: "file_1", ImageKey: "img_1", FileName: "a.mp4", Duration: 2000}},
+		{"sticker", `{"file_key": "file_1"}`, &StickerContent{FileKey: "file_1"}},
+		{"share_chat", `{"chat_id": "oc_1"}`, &ShareChatContent{ChatId: "oc_1"}},
+		{"share_user", `{"user_id": "ou_1"}`, &ShareUserContent{UserId: "ou_1"}},
+		{"merge_forward", `{"content": "Merged and Forwarded Message"}`, &MergeForwardContent{Content: "Merged and Forwarded Message"}},
+		{"interactive", `{"elements": []}`, &InteractiveContent{Raw: []byte(`{"elements": []}`)}},
+		{"location", `{"name": "x"}`, &UnknownContent{MsgType: "location", Raw: []byte(`{"name": "x"}`)}},
+	} {
+		c, err := ParseMessageContent(testCase.MsgType, testCase.Content)
+		assert.NoError(err, testCase.MsgType)
+		assert.Equal(testCase.Expected, c, testCase.MsgType)
+		assert.Equal(testCase.MsgType, c.MessageContentType())
+	}
+
+	// 错误
+	{
+		_, err := ParseMessageContent("text", `xxx`)
+		assert.Error(err)
+	}
+
+}
+
+func TestMessageReaction(t *testing.T) {
+	assert := assert.New(t)
+
+	raw := `{
+		"message_id": "om_1",
+		"reaction_type": {"emoji_type": "THUMBSUP"},
+		"operator_type": "user",
+		"user_id": {"union_id": "on_1", "user_id": "u1", "open_id": "ou_1"},
+		"action_time": "1627641418803"
+	}`
+	created := decodeEvent(assert, "im.message.reaction.created_v1", raw).(*MessageReactionCreatedV1)
+	assert.Equal("om_1", created.MessageId)
+	assert.Equal("THUMBSUP", created.ReactionType.EmojiType)
+	assert.Equal("ou_1", created.UserId.OpenId)
+
+	deleted := decodeEvent(assert, "im.message.reaction.deleted_v1", raw).(*MessageReactionDeletedV1)
+	assert.Equal(created.MessageReaction, deleted.MessageReaction)
+}
@@ -22,6 +22,65 @@ func Regist(regist func(string, func() interface{})) {
 	regist("p2p_chat_create", func() interface{} { return new(P2pChatCreate) })
 	regist("message", func() interface{} { return new(Message) })
 	regist("message_read", func() interface{} { return new(MessageRead) })
+	regist("im.message.receive_v1", func() interface{} { return new(MessageReceiveV1) })
+	regist("im.message.message_read_v1", func() interface{} { return new(MessageReadV1) })
+	regist("im.message.reaction.created_v1", func() interface{} { return new(MessageReactionCreatedV1) })
+	regist("im.message.reaction.deleted_v1", func() interface{} { return new(MessageReactionDeletedV1) })
+	// chat 群事件
+	regist("chat_disband", func() interface{} { return new(ChatDisband) })
+	regist("group_setting_update", func() interface{} { return new(GroupSettingUpdate) })
+	regist("add_user_to_chat", func() interface{} { return new(AddUserToChat) })
+	regist("remove_user_from_chat", func() interface{} { return new(RemoveUserFromChat) })
+	regist("revoke_add_user_from_chat", func() interface{} { return new(RevokeAddUserFromChat) })
+	regist("im.chat.disbanded_v1", func() interface{} { return new(ChatDisbandedV1) })
+	regist("im.chat.updated_v1", func() interface{} { return new(ChatUpdatedV1) })
+	regist("im.chat.member.user.added_v1", func() interface{} { return new(ChatMemberUserAddedV1) })
+	regist("im.chat.member.user.deleted_v1", func() interface{} { return new(ChatMemberUserDeletedV1) })
+	regist("im.chat.member.user.withdrawn_v1", func() interface{} { return new(ChatMemberUserWithdrawnV1) })
+	regist("im.chat.member.bot.added_v1", func() interface{} { return new(ChatMemberBotAddedV1) })
+	regist("im.chat.member.bot.deleted_v1", func() interface{} { return new(ChatMemberBotDeletedV1) })
+	// approval 审批事件
+	regist("approval", func() interface{} { return new(Approval) })
+	regist("approval_instance", func() interface{} { return new(ApprovalInstance) })
+	regist("approval_task", func() interface{} { return new(ApprovalTask) })
+	regist("approval_cc", func() interface{} { return new(ApprovalCc) })
+	regist("leave_approval", func() interface{} { return new(LeaveApproval) })
+	regist("leave_approvalV2", func() interface{} { return new(LeaveApprovalV2) })
+	regist("work_approval", func() interface{} { return new(WorkApproval) })
+	regist("shift_approval", func() interface{} { return new(ShiftApproval) })
+	regist("remedy_approval", func() interface{} { return new(RemedyApproval) })
+	regist("trip_approval", func() interface{} { return new(TripApproval) })
+	regist("out_approval", func() interface{} { return new(OutApproval) })
+	// calendar 日历事件
+	regist("calendar.calendar.changed_v4", func() interface{} { return new(CalendarChangedV4) })
+	regist("calendar.calendar.acl.created_v4", func() interface{} { return new(CalendarAclCreatedV4) })
+	regist("calendar.calendar.acl.deleted_v4", func() interface{} { return new(CalendarAclDeletedV4) })
+	regist("calendar.calendar.event.changed_v4", func() interface{} { return new(CalendarEventChangedV4) })
+	// meeting room 会议室事件
+	regist("meeting_room.meeting_room.created_v1", func() interface{} { return new(MeetingRoomCreatedV1) })
+	regist("meeting_room.meeting_room.updated_v1", func() interface{} { return new(MeetingRoomUpdatedV1) })
+	regist("meeting_room.meeting_room.deleted_v1", func() interface{} { return new(MeetingRoomDeletedV1) })
+	regist("meeting_room.meeting_room.status_changed_v1", func() interface{} { return new(MeetingRoomStatusChangedV1) })
+	// vc 视频会议事件
+	regist("vc.meeting.meeting_started_v1", func() interface{} { return new(MeetingStartedV1) })
+	regist("vc.meeting.meeting_ended_v1", func() interface{} { return new(MeetingEndedV1) })
+	regist("vc.meeting.join_meeting_v1", func() interface{} { return new(JoinMeetingV1) })
+	regist("vc.meeting.leave_meeting_v1", func() interface{} { return new(LeaveMeetingV1) })
+	regist("vc.meeting.share_started_v1", func() interface{} { return new(ShareStartedV1) })
+	regist("vc.meeting.share_ended_v1", func() interface{} { return new(ShareEndedV1) })
+	regist("vc.meeting.recording_started_v1", func() interface{} { return new(RecordingStartedV1) })
+	regist("vc.meeting.recording_ended_v1", func() interface{} { return new(RecordingEndedV1) })
+	regist("vc.meeting.recording_ready_v1", func() interface{} { return new(RecordingReadyV1) })
+	// drive 云文档事件
+	regist("drive.file.edit_v1", func() interface{} { return new(FileEditV1) })
+	regist("drive.file.read_v1", func() interface{} { return new(FileReadV1) })
+	regist("drive.file.title_updated_v1", func() interface{} { return new(FileTitleUpdatedV1) })
+	regist("drive.file.permission_member_added_v1", func() interface{} { return new(FilePermissionMemberAddedV1) })
+	regist("drive.file.permission_member_removed_v1", func() interface{} { return new(FilePermissionMemberRemovedV1) })
+	regist("drive.file.trashed_v1", func() interface{} { return new(FileTrashedV1) })
+	regist("drive.file.deleted_v1", func() interface{} { return new(FileDeletedV1) })
+	regist("drive.file.bitable_record_changed_v1", func() interface{} { return new(BitableRecordChangedV1) })
+	regist("drive.file.bitable_field_changed_v1", func() interface{} { return new(BitableFieldChangedV1) })
 	// unsupported
 	regist("", func() interface{} { return new(Unsupported) })
 }
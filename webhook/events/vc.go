@@ -0,0 +1,88 @@
+package events
+
+// MeetingStartedV1 会议开始 (2.0 版本 vc.meeting.meeting_started_v1) https://open.feishu.cn/document/uAjLw4CM/ukTMukTMukTM/reference/vc-v1/meeting/events/meeting_started
+type MeetingStartedV1 struct {
+	Meeting  VcMeeting `json:"meeting"`
+	Operator VcUser    `json:"operator"`
+}
+
+// MeetingEndedV1 会议结束 (2.0 版本 vc.meeting.meeting_ended_v1) https://open.feishu.cn/document/uAjLw4CM/ukTMukTMukTM/reference/vc-v1/meeting/events/meeting_ended
+type MeetingEndedV1 struct {
+	Meeting  VcMeeting `json:"meeting"`
+	Operator VcUser    `json:"operator"`
+}
+
+// JoinMeetingV1 加入会议 (2.0 版本 vc.meeting.join_meeting_v1) https://open.feishu.cn/document/uAjLw4CM/ukTMukTMukTM/reference/vc-v1/meeting/events/join_meeting
+type JoinMeetingV1 struct {
+	Meeting  VcMeeting `json:"meeting"`
+	Operator VcUser    `json:"operator"`
+}
+
+// LeaveMeetingV1 离开会议 (2.0 版本 vc.meeting.leave_meeting_v1) https://open.feishu.cn/document/uAjLw4CM/ukTMukTMukTM/reference/vc-v1/meeting/events/leave_meeting
+type LeaveMeetingV1 struct {
+	Meeting  VcMeeting `json:"meeting"`
+	Operator VcUser    `json:"operator"`
+
+	// LeaveReason 离开原因: 1-主动离会，2-会议结束，3-被踢出
+	LeaveReason int `json:"leave_reason"`
+}
+
+// ShareStartedV1 开始共享 (2.0 版本 vc.meeting.share_started_v1) https://open.feishu.cn/document/uAjLw4CM/ukTMukTMukTM/reference/vc-v1/meeting/events/share_started
+type ShareStartedV1 struct {
+	Meeting  VcMeeting `json:"meeting"`
+	Operator VcUser    `json:"operator"`
+}
+
+// ShareEndedV1 结束共享 (2.0 版本 vc.meeting.share_ended_v1) https://open.feishu.cn/document/uAjLw4CM/ukTMukTMukTM/reference/vc-v1/meeting/events/share_ended
+type ShareEndedV1 struct {
+	Meeting  VcMeeting `json:"meeting"`
+	Operator VcUser    `json:"operator"`
+}
+
+// RecordingStartedV1 录制开始 (2.0 版本 vc.meeting.recording_started_v1) https://open.feishu.cn/document/uAjLw4CM/ukTMukTMukTM/reference/vc-v1/meeting/events/recording_started
+type RecordingStartedV1 struct {
+	Meeting  VcMeeting `json:"meeting"`
+	Operator VcUser    `json:"operator"`
+}
+
+// RecordingEndedV1 录制结束 (2.0 版本 vc.meeting.recording_ended_v1) https://open.feishu.cn/document/uAjLw4CM/ukTMukTMukTM/reference/vc-v1/meeting/events/recording_ended
+type RecordingEndedV1 struct {
+	Meeting  VcMeeting `json:"meeting"`
+	Operator VcUser    `json:"operator"`
+}
+
+// RecordingReadyV1 录制完成 (2.0 版本 vc.meeting.recording_ready_v1) https://open.feishu.cn/document/uAjLw4CM/ukTMukTMukTM/reference/vc-v1/meeting/events/recording_ready
+type RecordingReadyV1 struct {
+	Meeting VcMeeting `json:"meeting"`
+
+	// URL 是录制文件地址
+	URL string `json:"url"`
+
+	// Duration 是录制总时长, 单位 ms
+	Duration string `json:"duration"`
+}
+
+// VcMeeting 是视频会议事件中的会议信息
+type VcMeeting struct {
+	Id        string `json:"id"`
+	Topic     string `json:"topic"`
+	MeetingNo string `json:"meeting_no"`
+
+	// StartTime/EndTime 是秒级时间戳
+	StartTime string `json:"start_time"`
+	EndTime   string `json:"end_time"`
+
+	HostUser VcUser `json:"host_user"`
+	Owner    VcUser `json:"owner"`
+}
+
+// VcUser 是视频会议事件中的用户
+type VcUser struct {
+	Id UserId `json:"id"`
+
+	// UserRole 用户会中角色: 1-普通参会人，2-主持人，3-联席主持人
+	UserRole int `json:"user_role"`
+
+	// UserType 用户类型: 1-lark用户，2-rooms用户，3-文档用户，4-neo单品用户，5-neo单品游客用户，6-pstn用户，7-sip用户
+	UserType int `json:"user_type"`
+}
@@ -0,0 +1,78 @@
+package webhook
+
+import (
+	"fmt"
+)
+
+// HandlerOption 是创建 Handler 的选项
+type HandlerOption func(*Handler) error
+
+// HOnDecodeError 在事件无法解析到注册的类型时回调，payload.RawEvent 包含原始事件内容.
+// 默认情况下此时会返回 500 让飞书稍后重试，可以使用 HFallbackRawEvent 改变此行为
+func HOnDecodeError(fn func(payload *Payload, err error)) HandlerOption {
+	return func(h *Handler) error {
+		if fn == nil {
+			fn = func(*Payload, error) {}
+		}
+		h.onDecodeError = fn
+		return nil
+	}
+}
+
+// HFallbackRawEvent 设置在事件无法解析时，是否仍然交给 PayloadHandler 处理，
+// 此时 payload.GetEvent() 返回 *UndecodableEvent
+func HFallbackRawEvent(fallback bool) HandlerOption {
+	return func(h *Handler) error {
+		h.fallbackRawEvent = fallback
+		return nil
+	}
+}
+
+// HMetricsHook 在每次解析事件后回调，typ 是事件类型，err 非 nil 表示解析失败，
+// 可用于收集指标
+func HMetricsHook(fn func(typ string, err error)) HandlerOption {
+	return func(h *Handler) error {
+		if fn == nil {
+			fn = func(string, error) {}
+		}
+		h.metricsHook = fn
+		return nil
+	}
+}
+
+// HRegistry 设置 Handler 使用的事件类型注册表 (默认 DefaultRegistry)
+func HRegistry(registry *Registry) HandlerOption {
+	return func(h *Handler) error {
+		if registry == nil {
+			return fmt.Errorf("HRegistry got nil registry")
+		}
+		h.registry = registry
+		h.ownedRegistry = false
+		return nil
+	}
+}
+
+// HRegistEv 为该 Handler 注册 (或覆盖) 一种事件类型，不影响其它 Handler 以及注册表本身:
+// 首次使用时会复制一份当前的注册表
+func HRegistEv(typ string, newEv func() interface{}) HandlerOption {
+	return func(h *Handler) error {
+		if newEv == nil {
+			return fmt.Errorf("HRegistEv got nil newEv for %q", typ)
+		}
+		if !h.ownedRegistry {
+			h.registry = h.registry.Clone()
+			h.ownedRegistry = true
+		}
+		h.registry.Regist(typ, newEv)
+		return nil
+	}
+}
+
+// HJournal 设置 journal, Handler 会将每个校验及解密后的 payload 记录到其中，之后可用 Replay 重放;
+// 记录失败时返回 500 让飞书稍后重试
+func HJournal(journal Journal) HandlerOption {
+	return func(h *Handler) error {
+		h.journal = journal
+		return nil
+	}
+}
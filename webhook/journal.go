@@ -0,0 +1,146 @@
+package webhook
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"sync"
+	"time"
+)
+
+var (
+	_ Journal = (*JSONLinesJournal)(nil)
+)
+
+// JournalEntry 是 journal 中的一条记录
+type JournalEntry struct {
+	// ReceivedAt 是收到请求的时间
+	ReceivedAt time.Time `json:"received_at"`
+
+	// Header 是请求头部
+	Header http.Header `json:"header"`
+
+	// Payload 是校验及解密后的明文 payload
+	Payload json.RawMessage `json:"payload"`
+}
+
+// Journal 用于记录 payload, 需要可并发使用
+type Journal interface {
+	// Append 追加一条记录
+	Append(entry *JournalEntry) error
+}
+
+// JSONLinesJournal 将记录以 json lines 格式 (每行一个 json) 写入 io.Writer
+type JSONLinesJournal struct {
+	mu sync.Mutex
+	w  io.Writer
+}
+
+// ReplayFilter 用于选择需要重放的记录，各条件之间是 "与" 的关系，零值表示不限
+type ReplayFilter struct {
+	// Types 是事件类型
+	Types []string
+
+	// Since/Until 是收到请求时间的范围 [Since, Until)
+	Since time.Time
+	Until time.Time
+
+	// UUIDs 是事件的唯一标识
+	UUIDs []string
+}
+
+// NewJSONLinesJournal 创建一个 JSONLinesJournal
+func NewJSONLinesJournal(w io.Writer) *JSONLinesJournal {
+	return &JSONLinesJournal{w: w}
+}
+
+// Append 满足 Journal 接口
+func (j *JSONLinesJournal) Append(entry *JournalEntry) error {
+	line, err := json.Marshal(entry)
+	if err != nil {
+		return err
+	}
+	line = append(line, '\n')
+
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	_, err = j.w.Write(line)
+	return err
+}
+
+// Match 判断 payload 是否满足条件
+func (filter *ReplayFilter) Match(entry *JournalEntry, payload *Payload) bool {
+	if !filter.Since.IsZero() && entry.ReceivedAt.Before(filter.Since) {
+		return false
+	}
+	if !filter.Until.IsZero() && !entry.ReceivedAt.Before(filter.Until) {
+		return false
+	}
+	if len(filter.Types) != 0 && !containsString(filter.Types, payload.EventType()) {
+		return false
+	}
+	if len(filter.UUIDs) != 0 && !containsString(filter.UUIDs, payload.UUID) {
+		return false
+	}
+	return true
+}
+
+// Replay 读取 JSONLinesJournal 格式的记录，跳过校验，将满足 filter 的 event_callback payload 重新解析为注册的事件类型后
+// 交给 fn 处理; fn 为 nil 时交给该 Handler 的 PayloadHandler 处理 (响应非 2xx 视为错误).
+//
+// 遇到错误时停止并返回，返回值 n 是已成功重放的数量
+func (h *Handler) Replay(ctx context.Context, r io.Reader, filter ReplayFilter, fn PayloadFunc) (n int, err error) {
+
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), 16*1024*1024)
+	lineNo := 0
+	for scanner.Scan() {
+		lineNo++
+		line := bytes.TrimSpace(scanner.Bytes())
+		if len(line) == 0 {
+			continue
+		}
+
+		entry := &JournalEntry{}
+		if err := json.Unmarshal(line, entry); err != nil {
+			return n, fmt.Errorf("Journal line %d: %s", lineNo, err)
+		}
+
+		payload, err := parsePayload(entry.Payload)
+		if err != nil {
+			return n, fmt.Errorf("Journal line %d: %s", lineNo, err)
+		}
+		if payload.Type != PayloadTypeEventCallback || !filter.Match(entry, payload) {
+			continue
+		}
+
+		if err := h.decodeEvent(payload); err != nil {
+			return n, fmt.Errorf("Journal line %d: %s", lineNo, err)
+		}
+
+		if fn != nil {
+			err = fn(ctx, payload)
+		} else {
+			err = h.dispatch(ctx, entry.Header, entry.Payload, payload)
+		}
+		if err != nil {
+			return n, fmt.Errorf("Journal line %d: %s", lineNo, err)
+		}
+		n++
+	}
+
+	return n, scanner.Err()
+}
+
+func containsString(strs []string, s string) bool {
+	for _, str := range strs {
+		if str == s {
+			return true
+		}
+	}
+	return false
+}
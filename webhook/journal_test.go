@@ -0,0 +1,93 @@
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/huangjunwen/feishu-driver/conf"
+	"github.com/huangjunwen/feishu-driver/webhook/events"
+)
+
+func TestJournalAndReplay(t *testing.T) {
+	assert := assert.New(t)
+
+	verifToken := "GzhQEyfUcx7eEungQFWtXgCbxSpUOJIb"
+	cnf := conf.NewWebhookConfig(verifToken, "")
+
+	journal := &bytes.Buffer{}
+	h := New(cnf, nil, HJournal(NewJSONLinesJournal(journal)))
+
+	post := func(uuid, event string) {
+		resp, _ := h.Handle(http.Header{"X-Test": []string{uuid}}, []byte(fmt.Sprintf(`{
+			"uuid": "%s",
+			"token": "%s",
+			"ts": "1589970805.376395",
+			"type": "event_callback",
+			"event": %s
+		}`, uuid, verifToken, event)))
+		assert.Equal(200, resp.StatusCode)
+	}
+
+	// token 错误的不会被记录
+	resp, _ := h.Handle(nil, []byte(`{"uuid": "0", "token": "xxx", "type": "event_callback", "event": {}}`))
+	assert.Equal(StatusInvalidToken, resp.StatusCode)
+
+	post("1", `{"type": "user_add", "open_id": "ou_1"}`)
+	post("2", `{"type": "user_leave", "open_id": "ou_2"}`)
+	mid := time.Now()
+	time.Sleep(time.Millisecond)
+	post("3", `{"type": "user_add", "open_id": "ou_3"}`)
+	assert.Equal(3, strings.Count(journal.String(), "\n"))
+
+	replay := func(filter ReplayFilter) []string {
+		openIds := []string{}
+		n, err := h.Replay(context.Background(), bytes.NewReader(journal.Bytes()), filter, func(ctx context.Context, payload *Payload) error {
+			switch ev := payload.GetEvent().(type) {
+			case *events.UserAdd:
+				openIds = append(openIds, ev.OpenId)
+			case *events.UserLeave:
+				openIds = append(openIds, ev.OpenId)
+			}
+			return nil
+		})
+		assert.NoError(err)
+		assert.Equal(len(openIds), n)
+		return openIds
+	}
+
+	assert.Equal([]string{"ou_1", "ou_2", "ou_3"}, replay(ReplayFilter{}))
+	assert.Equal([]string{"ou_1", "ou_3"}, replay(ReplayFilter{Types: []string{"user_add"}}))
+	assert.Equal([]string{"ou_2"}, replay(ReplayFilter{UUIDs: []string{"2"}}))
+	assert.Equal([]string{"ou_1", "ou_2"}, replay(ReplayFilter{Until: mid}))
+	assert.Equal([]string{"ou_3"}, replay(ReplayFilter{Since: mid, Types: []string{"user_add"}}))
+
+	// 重放到 PayloadHandler
+	{
+		received := []string{}
+		h2 := New(cnf, func(w http.ResponseWriter, r *http.Request, payload *Payload) {
+			received = append(received, r.Header.Get("X-Test"))
+			if payload.UUID == "3" {
+				http.Error(w, "bug", 500)
+				return
+			}
+			w.Write([]byte("ok"))
+		})
+		n, err := h2.Replay(context.Background(), bytes.NewReader(journal.Bytes()), ReplayFilter{}, nil)
+		assert.Error(err)
+		assert.Equal(2, n)
+		assert.Equal([]string{"1", "2", "3"}, received)
+	}
+
+	// 错误的记录
+	{
+		_, err := h.Replay(context.Background(), strings.NewReader("xxx\n"), ReplayFilter{}, nil)
+		assert.Error(err)
+	}
+}
@@ -0,0 +1,450 @@
+package longconn
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"math/rand"
+	"net/http"
+	"net/url"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/huangjunwen/golibs/logr"
+
+	"github.com/huangjunwen/feishu-driver/conf"
+	"github.com/huangjunwen/feishu-driver/utils"
+	"github.com/huangjunwen/feishu-driver/webhook"
+)
+
+var (
+	// DefaultEndpointURL 是获取长连接地址的接口
+	DefaultEndpointURL = "https://open.feishu.cn/callback/ws/endpoint"
+
+	// DefaultPingInterval 是服务端没有下发配置时默认的心跳间隔
+	DefaultPingInterval = 2 * time.Minute
+
+	// DefaultReconnectInterval 是默认的重连间隔
+	DefaultReconnectInterval = 10 * time.Second
+
+	// MaxFrameParts 是一条消息的最大分片数, 超过时丢弃
+	MaxFrameParts = 64
+
+	// FramePartsTTL 是未收齐的分片的保留时间
+	FramePartsTTL = time.Minute
+
+	// MaxPendingMessages 是一个连接上同时保留的未收齐分片的消息数, 超过时丢弃最早的
+	MaxPendingMessages = 256
+)
+
+// Client 是长连接客户端 (仅企业自建应用可用): 它使用应用凭证获取长连接地址并建立 WebSocket 连接，
+// 维持心跳/断线重连，解析收到的事件帧并回复 ack.
+//
+// 事件使用 handler 解析 (注册表/解析错误策略等选项均有效，但不会校验 Verification Token)，然后交给 fn 处理,
+// fn 返回错误时 ack 的状态码为 500
+type Client struct {
+	appConfig conf.AppConfig
+	handler   *webhook.Handler
+	fn        webhook.PayloadFunc
+
+	endpointURL       string
+	dialer            *websocket.Dialer
+	pingInterval      time.Duration // 0 表示使用服务端下发的配置
+	reconnectInterval time.Duration // 服务端没有下发重连间隔时使用
+	logger            logr.Logger
+
+	serverConfigMu sync.Mutex
+	serverConfig   *ClientConfig // 服务端最近一次下发的配置
+}
+
+// ClientConfig 是服务端下发的客户端配置, 时间单位均为秒
+type ClientConfig struct {
+	// ReconnectCount 是连接断开后 (连续) 重连的最大次数, 不大于 0 时不限
+	ReconnectCount int `json:"ReconnectCount"`
+
+	// ReconnectInterval 是重连间隔, 不大于 0 时使用 LCReconnectInterval 的设置
+	ReconnectInterval int `json:"ReconnectInterval"`
+
+	// ReconnectNonce 是重连间隔的随机抖动上限, 实际间隔为 ReconnectInterval 加上 [0, ReconnectNonce) 内的随机值
+	ReconnectNonce int `json:"ReconnectNonce"`
+
+	// PingInterval 是心跳间隔
+	PingInterval int `json:"PingInterval"`
+}
+
+// EndpointResult 是获取长连接地址接口的结果
+type EndpointResult struct {
+	utils.APIResultBase
+
+	Data struct {
+		URL          string        `json:"URL"`
+		ClientConfig *ClientConfig `json:"ClientConfig"`
+	} `json:"data"`
+}
+
+// ackResponse 是回复给服务端的 ack 内容
+type ackResponse struct {
+	StatusCode int               `json:"code"`
+	Headers    map[string]string `json:"headers"`
+	Data       []byte            `json:"data"`
+}
+
+// partialMessage 是未收齐分片的消息
+type partialMessage struct {
+	parts     [][]byte
+	createdAt time.Time
+}
+
+// conn 是一次连接的状态
+type conn struct {
+	*websocket.Conn
+	serviceId int32
+
+	writeMu sync.Mutex
+
+	partsMu sync.Mutex
+	parts   map[string]*partialMessage // message_id -> 分片
+
+	pingIntervalCh chan time.Duration
+}
+
+// NewClient 创建 Client, 需要调用 Run 开始接收事件; fn 为 nil 时使用 handler.PayloadFunc()
+func NewClient(cnf conf.AppConfig, handler *webhook.Handler, fn webhook.PayloadFunc, opts ...ClientOption) (*Client, error) {
+	if handler == nil {
+		return nil, fmt.Errorf("Missing handler")
+	}
+	if fn == nil {
+		fn = handler.PayloadFunc()
+	}
+	c := &Client{
+		appConfig:         cnf,
+		handler:           handler,
+		fn:                fn,
+		endpointURL:       DefaultEndpointURL,
+		dialer:            websocket.DefaultDialer,
+		reconnectInterval: DefaultReconnectInterval,
+		logger:            logr.Nop,
+	}
+	for _, opt := range opts {
+		if err := opt(c); err != nil {
+			return nil, err
+		}
+	}
+	return c, nil
+}
+
+// Run 建立长连接并接收事件，断开后按服务端下发的配置 (重连间隔/抖动/次数) 重连，直到 ctx 结束;
+// 连续重连失败的次数超过服务端下发的 ReconnectCount 时返回最后一次的错误
+func (c *Client) Run(ctx context.Context) error {
+	retries := 0
+	for {
+		connected, err := c.runOnce(ctx)
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		c.logger.Error(err, "Long connection broken", "appId", c.appConfig.FeishuAppId())
+
+		if connected {
+			retries = 0
+		}
+		retries++
+		maxRetries, wait := c.reconnectPolicy()
+		if maxRetries > 0 && retries > maxRetries {
+			return fmt.Errorf("Long connection reconnect %d times failed: %s", maxRetries, err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(wait):
+		}
+	}
+}
+
+// reconnectPolicy 根据服务端下发的配置返回最大重连次数 (0 表示不限) 及本次重连前的等待时间
+func (c *Client) reconnectPolicy() (maxRetries int, wait time.Duration) {
+	c.serverConfigMu.Lock()
+	cfg := c.serverConfig
+	c.serverConfigMu.Unlock()
+
+	wait = c.reconnectInterval
+	if cfg == nil {
+		return 0, wait
+	}
+	if cfg.ReconnectInterval > 0 {
+		wait = time.Duration(cfg.ReconnectInterval) * time.Second
+	}
+	if cfg.ReconnectNonce > 0 {
+		wait += time.Duration(rand.Int63n(int64(time.Duration(cfg.ReconnectNonce) * time.Second)))
+	}
+	return cfg.ReconnectCount, wait
+}
+
+// GetEndpoint 调接口获得长连接地址
+func (c *Client) GetEndpoint(ctx context.Context) (*EndpointResult, error) {
+	body := &struct {
+		AppId     string `json:"AppID"`
+		AppSecret string `json:"AppSecret"`
+	}{
+		AppId:     c.appConfig.FeishuAppId(),
+		AppSecret: c.appConfig.FeishuAppSecret(),
+	}
+	buf := &bytes.Buffer{}
+	if err := json.NewEncoder(buf).Encode(body); err != nil {
+		return nil, err
+	}
+
+	// NOTE: 该接口不在 URLBase 下，这里仅使用 APIOptions 中的 http client
+	req, err := http.NewRequestWithContext(ctx, "POST", c.endpointURL, buf)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := utils.CtxAPIOptions(ctx).Client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	result := &EndpointResult{}
+	if err := json.NewDecoder(resp.Body).Decode(result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// runOnce 建立一次连接并接收事件直到断开, connected 表示是否成功建立了连接
+func (c *Client) runOnce(ctx context.Context) (connected bool, err error) {
+	res, err := c.GetEndpoint(ctx)
+	if err != nil {
+		return false, err
+	}
+	if err := res.ResultError(); err != nil {
+		return false, err
+	}
+
+	if cfg := res.Data.ClientConfig; cfg != nil {
+		c.setServerConfig(cfg)
+	}
+
+	u, err := url.Parse(res.Data.URL)
+	if err != nil {
+		return false, err
+	}
+	serviceId, err := strconv.ParseInt(u.Query().Get("service_id"), 10, 32)
+	if err != nil {
+		return false, fmt.Errorf("Invalid service_id in endpoint url: %s", err)
+	}
+
+	wsConn, _, err := c.dialer.DialContext(ctx, res.Data.URL, nil)
+	if err != nil {
+		return false, err
+	}
+	defer wsConn.Close()
+	c.logger.Info("Long connection connected", "appId", c.appConfig.FeishuAppId())
+
+	cn := &conn{
+		Conn:           wsConn,
+		serviceId:      int32(serviceId),
+		parts:          map[string]*partialMessage{},
+		pingIntervalCh: make(chan time.Duration, 1),
+	}
+	if cfg := res.Data.ClientConfig; cfg != nil {
+		c.updateConfig(cn, cfg)
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	go func() {
+		<-ctx.Done()
+		wsConn.Close()
+	}()
+	go c.ping(ctx, cn)
+
+	for {
+		mt, data, err := wsConn.ReadMessage()
+		if err != nil {
+			return true, err
+		}
+		if mt != websocket.BinaryMessage {
+			continue
+		}
+
+		frame := &Frame{}
+		if err := frame.Unmarshal(data); err != nil {
+			c.logger.Error(err, "Invalid frame")
+			continue
+		}
+
+		switch frame.Method {
+		case FrameMethodControl:
+			if frame.Header(FrameHeaderType) == FrameTypePong && len(frame.Payload) != 0 {
+				cfg := &ClientConfig{}
+				if err := json.Unmarshal(frame.Payload, cfg); err == nil {
+					c.updateConfig(cn, cfg)
+				}
+			}
+
+		case FrameMethodData:
+			go c.handleData(ctx, cn, frame)
+		}
+	}
+}
+
+func (c *Client) setServerConfig(cfg *ClientConfig) {
+	c.serverConfigMu.Lock()
+	c.serverConfig = cfg
+	c.serverConfigMu.Unlock()
+}
+
+func (c *Client) updateConfig(cn *conn, cfg *ClientConfig) {
+	c.setServerConfig(cfg)
+	if c.pingInterval != 0 || cfg.PingInterval <= 0 {
+		return
+	}
+	select {
+	case <-cn.pingIntervalCh:
+	default:
+	}
+	cn.pingIntervalCh <- time.Duration(cfg.PingInterval) * time.Second
+}
+
+func (c *Client) ping(ctx context.Context, cn *conn) {
+	interval := c.pingInterval
+	if interval == 0 {
+		interval = DefaultPingInterval
+	}
+	timer := time.NewTimer(0)
+	defer timer.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+
+		case newInterval := <-cn.pingIntervalCh:
+			interval = newInterval
+
+		case <-timer.C:
+			frame := &Frame{
+				Service: cn.serviceId,
+				Method:  FrameMethodControl,
+			}
+			frame.SetHeader(FrameHeaderType, FrameTypePing)
+			if err := cn.writeFrame(frame); err != nil {
+				c.logger.Error(err, "Ping error")
+			}
+			timer.Reset(interval)
+		}
+	}
+}
+
+func (c *Client) handleData(ctx context.Context, cn *conn, frame *Frame) {
+	data, complete := cn.combine(frame)
+	if !complete {
+		return
+	}
+
+	start := time.Now()
+	code := 200
+	switch frame.Header(FrameHeaderType) {
+	case FrameTypeEvent:
+		if err := c.handleEvent(ctx, data); err != nil {
+			c.logger.Error(err, "Handle event error", "messageId", frame.Header(FrameHeaderMessageId))
+			code = 500
+		}
+	default:
+		// card 等其它类型暂不支持，直接 ack
+	}
+
+	bizRt := strconv.FormatInt(int64(time.Since(start)/time.Millisecond), 10)
+	ack, err := json.Marshal(&ackResponse{
+		StatusCode: code,
+		Headers:    map[string]string{FrameHeaderBizRt: bizRt},
+	})
+	if err != nil {
+		panic(err)
+	}
+	frame.Payload = ack
+	frame.SetHeader(FrameHeaderBizRt, bizRt)
+	if err := cn.writeFrame(frame); err != nil {
+		c.logger.Error(err, "Ack error", "messageId", frame.Header(FrameHeaderMessageId))
+	}
+}
+
+func (c *Client) handleEvent(ctx context.Context, data []byte) error {
+	payload, err := c.handler.ParsePayload(data)
+	if err != nil {
+		return err
+	}
+	return c.fn(ctx, payload)
+}
+
+func (cn *conn) writeFrame(frame *Frame) error {
+	cn.writeMu.Lock()
+	defer cn.writeMu.Unlock()
+	return cn.WriteMessage(websocket.BinaryMessage, frame.Marshal())
+}
+
+// combine 合并分片, 所有分片都收到后返回完整数据
+func (cn *conn) combine(frame *Frame) ([]byte, bool) {
+	sum, _ := strconv.Atoi(frame.Header(FrameHeaderSum))
+	if sum <= 1 {
+		return frame.Payload, true
+	}
+	if sum > MaxFrameParts {
+		return nil, false
+	}
+	seq, err := strconv.Atoi(frame.Header(FrameHeaderSeq))
+	if err != nil || seq < 0 || seq >= sum {
+		return nil, false
+	}
+
+	cn.partsMu.Lock()
+	defer cn.partsMu.Unlock()
+
+	now := time.Now()
+	messageId := frame.Header(FrameHeaderMessageId)
+	msg := cn.parts[messageId]
+	if msg == nil || len(msg.parts) != sum || now.Sub(msg.createdAt) > FramePartsTTL {
+		cn.evictParts(now)
+		msg = &partialMessage{
+			parts:     make([][]byte, sum),
+			createdAt: now,
+		}
+		cn.parts[messageId] = msg
+	}
+	msg.parts[seq] = frame.Payload
+
+	for _, part := range msg.parts {
+		if part == nil {
+			return nil, false
+		}
+	}
+	delete(cn.parts, messageId)
+	return bytes.Join(msg.parts, nil), true
+}
+
+// evictParts 丢弃过期的分片, 并在消息数达到上限时丢弃最早的; 需要持有 cn.partsMu
+func (cn *conn) evictParts(now time.Time) {
+	var (
+		oldestId string
+		oldest   *partialMessage
+	)
+	for messageId, msg := range cn.parts {
+		if now.Sub(msg.createdAt) > FramePartsTTL {
+			delete(cn.parts, messageId)
+			continue
+		}
+		if oldest == nil || msg.createdAt.Before(oldest.createdAt) {
+			oldestId, oldest = messageId, msg
+		}
+	}
+	if len(cn.parts) >= MaxPendingMessages && oldest != nil {
+		delete(cn.parts, oldestId)
+	}
+}
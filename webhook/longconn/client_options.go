@@ -0,0 +1,67 @@
+package longconn
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/huangjunwen/golibs/logr"
+)
+
+// ClientOption 是创建 Client 的选项
+type ClientOption func(*Client) error
+
+// LCEndpointURL 设置获取长连接地址的接口, 默认为 DefaultEndpointURL
+func LCEndpointURL(endpointURL string) ClientOption {
+	return func(c *Client) error {
+		if endpointURL == "" {
+			return fmt.Errorf("LCEndpointURL: empty url")
+		}
+		c.endpointURL = endpointURL
+		return nil
+	}
+}
+
+// LCPingInterval 设置心跳间隔, 设置后将忽略服务端下发的心跳配置
+func LCPingInterval(interval time.Duration) ClientOption {
+	return func(c *Client) error {
+		if interval <= 0 {
+			return fmt.Errorf("LCPingInterval: interval must be positive")
+		}
+		c.pingInterval = interval
+		return nil
+	}
+}
+
+// LCReconnectInterval 设置服务端没有下发重连间隔时使用的重连间隔, 默认为 DefaultReconnectInterval
+func LCReconnectInterval(interval time.Duration) ClientOption {
+	return func(c *Client) error {
+		if interval < 0 {
+			return fmt.Errorf("LCReconnectInterval: interval must not be negative")
+		}
+		c.reconnectInterval = interval
+		return nil
+	}
+}
+
+// LCDialer 设置 WebSocket 的 dialer, 默认为 websocket.DefaultDialer
+func LCDialer(dialer *websocket.Dialer) ClientOption {
+	return func(c *Client) error {
+		if dialer == nil {
+			dialer = websocket.DefaultDialer
+		}
+		c.dialer = dialer
+		return nil
+	}
+}
+
+// LCLogger 设置 logger
+func LCLogger(logger logr.Logger) ClientOption {
+	return func(c *Client) error {
+		if logger == nil {
+			logger = logr.Nop
+		}
+		c.logger = logger
+		return nil
+	}
+}
@@ -0,0 +1,257 @@
+package longconn
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/huangjunwen/feishu-driver/conf"
+	"github.com/huangjunwen/feishu-driver/webhook"
+	"github.com/huangjunwen/feishu-driver/webhook/events"
+)
+
+func TestFrame(t *testing.T) {
+	assert := assert.New(t)
+
+	frame := &Frame{
+		SeqId:   1,
+		LogId:   2,
+		Service: 3,
+		Method:  FrameMethodData,
+		Payload: []byte(`{"a":1}`),
+	}
+	frame.SetHeader(FrameHeaderType, FrameTypeEvent)
+	frame.SetHeader(FrameHeaderSum, "1")
+	frame.SetHeader(FrameHeaderSum, "2")
+
+	decoded := &Frame{}
+	assert.NoError(decoded.Unmarshal(frame.Marshal()))
+	assert.Equal(frame, decoded)
+	assert.Equal("2", decoded.Header(FrameHeaderSum))
+	assert.Equal("", decoded.Header("xxx"))
+
+	assert.Error((&Frame{}).Unmarshal([]byte{0xff}))
+}
+
+func TestClient(t *testing.T) {
+	assert := assert.New(t)
+
+	eventBody := `{
+		"schema": "2.0",
+		"header": {
+			"event_id": "5e3702a84e847582be8db7fb73283c02",
+			"event_type": "im.chat.disbanded_v1",
+			"create_time": "1608725989000",
+			"token": "rvaYgkND1GOiu5MM0E1rncYC6PLtF7JV",
+			"app_id": "cli_a1",
+			"tenant_key": "736588c9260f175e"
+		},
+		"event": {"chat_id": "oc_1", "operator_id": {"open_id": "ou_1"}}
+	}`
+
+	var (
+		connCount int32
+		pings     int32
+		acks      = make(chan *Frame, 10)
+	)
+	upgrader := websocket.Upgrader{}
+	var srv *httptest.Server
+	srv = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/endpoint":
+			req := map[string]string{}
+			json.NewDecoder(r.Body).Decode(&req)
+			assert.Equal("cli_a1", req["AppID"])
+			assert.Equal("secret", req["AppSecret"])
+			fmt.Fprintf(w, `{"code":0,"msg":"ok","data":{"URL":"ws%s/ws?service_id=33","ClientConfig":{"PingInterval":120}}}`, strings.TrimPrefix(srv.URL, "http"))
+
+		case "/ws":
+			c, err := upgrader.Upgrade(w, r, nil)
+			if !assert.NoError(err) {
+				return
+			}
+			defer c.Close()
+			n := atomic.AddInt32(&connCount, 1)
+
+			// 分两片发送事件
+			for i, part := range []string{eventBody[:10], eventBody[10:]} {
+				frame := &Frame{Service: 33, Method: FrameMethodData, Payload: []byte(part)}
+				frame.SetHeader(FrameHeaderType, FrameTypeEvent)
+				frame.SetHeader(FrameHeaderMessageId, fmt.Sprintf("msg_%d", n))
+				frame.SetHeader(FrameHeaderSum, "2")
+				frame.SetHeader(FrameHeaderSeq, fmt.Sprint(i))
+				c.WriteMessage(websocket.BinaryMessage, frame.Marshal())
+			}
+
+			for {
+				_, data, err := c.ReadMessage()
+				if err != nil {
+					return
+				}
+				frame := &Frame{}
+				if !assert.NoError(frame.Unmarshal(data)) {
+					return
+				}
+				switch frame.Method {
+				case FrameMethodControl:
+					assert.Equal(FrameTypePing, frame.Header(FrameHeaderType))
+					assert.Equal(int32(33), frame.Service)
+					atomic.AddInt32(&pings, 1)
+					frame.SetHeader(FrameHeaderType, FrameTypePong)
+					c.WriteMessage(websocket.BinaryMessage, frame.Marshal())
+
+				case FrameMethodData:
+					acks <- frame
+					// 第一次连接收到 ack 后断开以测试重连
+					if n == 1 {
+						return
+					}
+				}
+			}
+		}
+	}))
+	defer srv.Close()
+
+	received := make(chan *events.ChatDisbandedV1, 10)
+	h := webhook.New(conf.NewWebhookConfig("rvaYgkND1GOiu5MM0E1rncYC6PLtF7JV", ""), nil)
+	client, err := NewClient(
+		conf.NewAppConfig("cli_a1", "secret"),
+		h,
+		func(ctx context.Context, payload *webhook.Payload) error {
+			ev := payload.GetEvent().(*events.ChatDisbandedV1)
+			received <- ev
+			if atomic.LoadInt32(&connCount) > 1 {
+				return fmt.Errorf("failed")
+			}
+			return nil
+		},
+		LCEndpointURL(srv.URL+"/endpoint"),
+		LCReconnectInterval(10*time.Millisecond),
+	)
+	assert.NoError(err)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() { done <- client.Run(ctx) }()
+
+	for i, expectCode := range []int{200, 500} {
+		select {
+		case ev := <-received:
+			assert.Equal("oc_1", ev.ChatId)
+		case <-time.After(5 * time.Second):
+			t.Fatal("event not received")
+		}
+
+		select {
+		case ack := <-acks:
+			assert.Equal(fmt.Sprintf("msg_%d", i+1), ack.Header(FrameHeaderMessageId))
+			assert.NotEmpty(ack.Header(FrameHeaderBizRt))
+			resp := &ackResponse{}
+			assert.NoError(json.Unmarshal(ack.Payload, resp))
+			assert.Equal(expectCode, resp.StatusCode)
+		case <-time.After(5 * time.Second):
+			t.Fatal("ack not received")
+		}
+	}
+	assert.True(atomic.LoadInt32(&pings) >= 1)
+
+	cancel()
+	assert.Equal(context.Canceled, <-done)
+
+	// 选项错误
+	_, err = NewClient(conf.NewAppConfig("cli_a1", "secret"), h, nil, LCPingInterval(0))
+	assert.Error(err)
+	_, err = NewClient(conf.NewAppConfig("cli_a1", "secret"), nil, nil)
+	assert.Error(err)
+}
+
+func TestCombine(t *testing.T) {
+	assert := assert.New(t)
+
+	cn := &conn{parts: map[string]*partialMessage{}}
+	part := func(messageId string, sum, seq int, payload string) *Frame {
+		frame := &Frame{Payload: []byte(payload)}
+		frame.SetHeader(FrameHeaderMessageId, messageId)
+		frame.SetHeader(FrameHeaderSum, fmt.Sprint(sum))
+		frame.SetHeader(FrameHeaderSeq, fmt.Sprint(seq))
+		return frame
+	}
+
+	// 正常合并
+	_, complete := cn.combine(part("m1", 2, 1, "b"))
+	assert.False(complete)
+	data, complete := cn.combine(part("m1", 2, 0, "a"))
+	assert.True(complete)
+	assert.Equal("ab", string(data))
+	assert.Len(cn.parts, 0)
+
+	// 分片数过大
+	_, complete = cn.combine(part("m2", MaxFrameParts+1, 0, "a"))
+	assert.False(complete)
+	assert.Len(cn.parts, 0)
+
+	// 消息数达到上限时丢弃最早的
+	for i := 0; i < MaxPendingMessages+10; i++ {
+		cn.combine(part(fmt.Sprintf("p%d", i), 2, 0, "a"))
+	}
+	assert.Len(cn.parts, MaxPendingMessages)
+	assert.Nil(cn.parts["p0"])
+
+	// 过期的分片被丢弃
+	for _, msg := range cn.parts {
+		msg.createdAt = msg.createdAt.Add(-2 * FramePartsTTL)
+	}
+	cn.combine(part("m3", 2, 0, "a"))
+	assert.Len(cn.parts, 1)
+	_, complete = cn.combine(part("p20", 2, 1, "b"))
+	assert.False(complete)
+}
+
+func TestClientReconnectPolicy(t *testing.T) {
+	assert := assert.New(t)
+
+	var endpointCalls int32
+	var srv *httptest.Server
+	srv = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/endpoint":
+			atomic.AddInt32(&endpointCalls, 1)
+			// ws 地址无法连接
+			fmt.Fprintf(w, `{"code":0,"data":{"URL":"ws%s/notfound?service_id=1","ClientConfig":{"ReconnectCount":2,"ReconnectInterval":0,"ReconnectNonce":0,"PingInterval":120}}}`, strings.TrimPrefix(srv.URL, "http"))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer srv.Close()
+
+	h := webhook.New(conf.NewWebhookConfig("token", ""), nil)
+	client, err := NewClient(conf.NewAppConfig("cli_a1", "secret"), h, nil,
+		LCEndpointURL(srv.URL+"/endpoint"),
+		LCReconnectInterval(time.Millisecond),
+	)
+	assert.NoError(err)
+
+	// 首次连接 + 重连 2 次后放弃
+	assert.Error(client.Run(context.Background()))
+	assert.Equal(int32(3), atomic.LoadInt32(&endpointCalls))
+
+	// 重连间隔及抖动
+	client.updateConfig(&conn{pingIntervalCh: make(chan time.Duration, 1)}, &ClientConfig{ReconnectCount: -1, ReconnectInterval: 1, ReconnectNonce: 2})
+	for i := 0; i < 10; i++ {
+		maxRetries, wait := client.reconnectPolicy()
+		assert.Equal(-1, maxRetries)
+		assert.True(wait >= time.Second && wait < 3*time.Second, wait)
+	}
+	client.updateConfig(&conn{pingIntervalCh: make(chan time.Duration, 1)}, &ClientConfig{})
+	_, wait := client.reconnectPolicy()
+	assert.Equal(time.Millisecond, wait)
+}
@@ -0,0 +1,3 @@
+// Package longconn 实现通过长连接 (WebSocket) 接收订阅事件，适用于无法暴露公网回调地址的服务,
+// 收到的事件与 webhook.Handler 使用相同的 Payload/事件类型解析流程
+package longconn
@@ -0,0 +1,207 @@
+package longconn
+
+import (
+	"encoding/binary"
+	"fmt"
+)
+
+var (
+	// FrameMethodControl 是控制帧 (ping/pong)
+	FrameMethodControl int32 = 0
+
+	// FrameMethodData 是数据帧 (event/card)
+	FrameMethodData int32 = 1
+)
+
+var (
+	FrameTypePing  = "ping"
+	FrameTypePong  = "pong"
+	FrameTypeEvent = "event"
+	FrameTypeCard  = "card"
+)
+
+var (
+	FrameHeaderType      = "type"
+	FrameHeaderMessageId = "message_id"
+	FrameHeaderSum       = "sum"
+	FrameHeaderSeq       = "seq"
+	FrameHeaderTraceId   = "trace_id"
+	FrameHeaderBizRt     = "biz_rt"
+)
+
+// Frame 是长连接中传输的帧, 使用 protobuf 编码 (pbbp2.Frame)
+type Frame struct {
+	SeqId           uint64        // 1
+	LogId           uint64        // 2
+	Service         int32         // 3
+	Method          int32         // 4
+	Headers         []FrameHeader // 5
+	PayloadEncoding string        // 6
+	PayloadType     string        // 7
+	Payload         []byte        // 8
+	LogIdNew        string        // 9
+}
+
+// FrameHeader 是帧的头部
+type FrameHeader struct {
+	Key   string // 1
+	Value string // 2
+}
+
+// Header 返回 key 对应的头部值
+func (f *Frame) Header(key string) string {
+	for _, h := range f.Headers {
+		if h.Key == key {
+			return h.Value
+		}
+	}
+	return ""
+}
+
+// SetHeader 设置头部，已存在则覆盖
+func (f *Frame) SetHeader(key, value string) {
+	for i := range f.Headers {
+		if f.Headers[i].Key == key {
+			f.Headers[i].Value = value
+			return
+		}
+	}
+	f.Headers = append(f.Headers, FrameHeader{Key: key, Value: value})
+}
+
+// Marshal 编码
+func (f *Frame) Marshal() []byte {
+	b := []byte{}
+	b = appendVarintField(b, 1, f.SeqId)
+	b = appendVarintField(b, 2, f.LogId)
+	b = appendVarintField(b, 3, uint64(int64(f.Service)))
+	b = appendVarintField(b, 4, uint64(int64(f.Method)))
+	for _, h := range f.Headers {
+		hb := []byte{}
+		hb = appendBytesField(hb, 1, []byte(h.Key))
+		hb = appendBytesField(hb, 2, []byte(h.Value))
+		b = appendBytesField(b, 5, hb)
+	}
+	if f.PayloadEncoding != "" {
+		b = appendBytesField(b, 6, []byte(f.PayloadEncoding))
+	}
+	if f.PayloadType != "" {
+		b = appendBytesField(b, 7, []byte(f.PayloadType))
+	}
+	if f.Payload != nil {
+		b = appendBytesField(b, 8, f.Payload)
+	}
+	if f.LogIdNew != "" {
+		b = appendBytesField(b, 9, []byte(f.LogIdNew))
+	}
+	return b
+}
+
+// Unmarshal 解码, 忽略未知字段
+func (f *Frame) Unmarshal(b []byte) error {
+	*f = Frame{}
+	return walkFields(b, func(num int, v uint64, data []byte) error {
+		switch num {
+		case 1:
+			f.SeqId = v
+		case 2:
+			f.LogId = v
+		case 3:
+			f.Service = int32(v)
+		case 4:
+			f.Method = int32(v)
+		case 5:
+			h := FrameHeader{}
+			err := walkFields(data, func(num int, _ uint64, data []byte) error {
+				switch num {
+				case 1:
+					h.Key = string(data)
+				case 2:
+					h.Value = string(data)
+				}
+				return nil
+			})
+			if err != nil {
+				return err
+			}
+			f.Headers = append(f.Headers, h)
+		case 6:
+			f.PayloadEncoding = string(data)
+		case 7:
+			f.PayloadType = string(data)
+		case 8:
+			f.Payload = append([]byte{}, data...)
+		case 9:
+			f.LogIdNew = string(data)
+		}
+		return nil
+	})
+}
+
+func appendVarintField(b []byte, num int, v uint64) []byte {
+	b = appendVarint(b, uint64(num)<<3|0)
+	return appendVarint(b, v)
+}
+
+func appendBytesField(b []byte, num int, data []byte) []byte {
+	b = appendVarint(b, uint64(num)<<3|2)
+	b = appendVarint(b, uint64(len(data)))
+	return append(b, data...)
+}
+
+func appendVarint(b []byte, v uint64) []byte {
+	buf := [binary.MaxVarintLen64]byte{}
+	n := binary.PutUvarint(buf[:], v)
+	return append(b, buf[:n]...)
+}
+
+// walkFields 遍历 protobuf 消息的字段, 对于 varint 字段 v 有效，对于 length-delimited 字段 data 有效
+func walkFields(b []byte, fn func(num int, v uint64, data []byte) error) error {
+	for len(b) > 0 {
+		key, n := binary.Uvarint(b)
+		if n <= 0 {
+			return fmt.Errorf("Invalid field key")
+		}
+		b = b[n:]
+		num, wireType := int(key>>3), key&7
+
+		var (
+			v    uint64
+			data []byte
+		)
+		switch wireType {
+		case 0: // varint
+			v, n = binary.Uvarint(b)
+			if n <= 0 {
+				return fmt.Errorf("Invalid varint of field %d", num)
+			}
+			b = b[n:]
+		case 1: // 64-bit
+			if len(b) < 8 {
+				return fmt.Errorf("Invalid fixed64 of field %d", num)
+			}
+			v = binary.LittleEndian.Uint64(b)
+			b = b[8:]
+		case 2: // length-delimited
+			l, n := binary.Uvarint(b)
+			if n <= 0 || uint64(len(b)-n) < l {
+				return fmt.Errorf("Invalid length of field %d", num)
+			}
+			data = b[n : n+int(l)]
+			b = b[n+int(l):]
+		case 5: // 32-bit
+			if len(b) < 4 {
+				return fmt.Errorf("Invalid fixed32 of field %d", num)
+			}
+			v = uint64(binary.LittleEndian.Uint32(b))
+			b = b[4:]
+		default:
+			return fmt.Errorf("Unsupported wire type %d of field %d", wireType, num)
+		}
+
+		if err := fn(num, v, data); err != nil {
+			return err
+		}
+	}
+	return nil
+}
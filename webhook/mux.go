@@ -0,0 +1,126 @@
+package webhook
+
+import (
+	"fmt"
+	"io/ioutil"
+	"net/http"
+
+	"github.com/huangjunwen/feishu-driver/conf"
+)
+
+var (
+	_ http.Handler = (*Mux)(nil)
+)
+
+// Mux 是多应用的 webhook 入口，可以让多个应用共用同一个回调地址:
+// 它依次尝试各应用的解密秘钥及 Verification Token，并根据 payload 中的 app_id 将请求分派给对应应用的 Handler
+type Mux struct {
+	apps []*muxApp
+}
+
+type muxApp struct {
+	appId   string
+	handler *Handler
+}
+
+// NewMux 创建一个空的 Mux, 使用 Add 添加应用
+func NewMux() *Mux {
+	return &Mux{}
+}
+
+// Add 添加一个应用, 参数与 New 相同, 返回该应用的 Handler (可作为该应用的 AppTicketProvider);
+// app_id 重复或选项错误时会 panic
+func (m *Mux) Add(appCnf conf.AppConfig, cnf conf.WebhookConfig, handler PayloadHandler, opts ...HandlerOption) *Handler {
+	appId := appCnf.FeishuAppId()
+	if appId == "" {
+		panic(fmt.Errorf("Empty feishu app id"))
+	}
+	if m.Handler(appId) != nil {
+		panic(fmt.Errorf("Duplicated feishu app id %q", appId))
+	}
+
+	h := New(cnf, handler, opts...)
+	m.apps = append(m.apps, &muxApp{
+		appId:   appId,
+		handler: h,
+	})
+	return h
+}
+
+// Handler 返回应用的 Handler, 不存在时返回 nil
+func (m *Mux) Handler(appId string) *Handler {
+	for _, app := range m.apps {
+		if app.appId == appId {
+			return app.handler
+		}
+	}
+	return nil
+}
+
+// AppTicketProvider 返回应用的 AppTicketProvider, 不存在时返回 nil
+func (m *Mux) AppTicketProvider(appId string) conf.AppTicketProvider {
+	h := m.Handler(appId)
+	if h == nil {
+		return nil
+	}
+	return h
+}
+
+// ServeHTTP 满足 http.Handler 接口, 基于 Handle 实现
+func (m *Mux) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+
+	body, err := ioutil.ReadAll(r.Body)
+	if err != nil {
+		http.Error(w, err.Error(), 500)
+		return
+	}
+
+	resp, payload, h := m.Handle(r.Header, body)
+	if payload == nil {
+		resp.Respond(w)
+		return
+	}
+	h.handler(w, r, payload)
+
+}
+
+// Handle 类似于 Handler.Handle, 额外返回匹配到的应用的 Handler (没有匹配时为 nil).
+//
+// 依次使用各应用 (按添加顺序) 校验请求，跳过解密失败/payload 解析失败/签名或 token 不匹配/payload 中的 app_id 不匹配的应用
+// (各应用的秘钥可以不同); url_verification 没有 app_id, 由第一个校验通过的应用处理.
+// 没有应用匹配时, 返回校验进行得最远的错误: StatusInvalidToken 优先于 StatusInvalidPayload, 其次是 StatusDecryptError
+func (m *Mux) Handle(header http.Header, body []byte) (resp *Response, payload *Payload, h *Handler) {
+
+	for _, app := range m.apps {
+		payload, plainText, errResp := app.handler.verify(header, body)
+		if errResp == nil {
+			if appId := payload.AppId(); appId == "" || appId == app.appId {
+				resp, payload = app.handler.process(header, plainText, payload)
+				return resp, payload, app.handler
+			}
+			errResp = newErrorResponse("Invalid payload", StatusInvalidToken)
+		}
+		if resp == nil || muxErrorRank(errResp.StatusCode) > muxErrorRank(resp.StatusCode) {
+			resp = errResp
+		}
+	}
+
+	if resp == nil {
+		resp = newErrorResponse("Invalid payload", StatusInvalidToken)
+	}
+	return resp, nil, nil
+
+}
+
+// muxErrorRank 返回校验错误的进度: 解密 < 解析 < token 校验
+func muxErrorRank(statusCode int) int {
+	switch statusCode {
+	case StatusDecryptError:
+		return 1
+	case StatusInvalidPayload:
+		return 2
+	case StatusInvalidToken:
+		return 3
+	}
+	return 0
+}
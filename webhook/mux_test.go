@@ -0,0 +1,162 @@
+package webhook
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/huangjunwen/feishu-driver/conf"
+)
+
+func TestMux(t *testing.T) {
+	assert := assert.New(t)
+
+	var (
+		botToken   = "GzhQEyfUcx7eEungQFWtXgCbxSpUOJIb"
+		storeToken = "rvaYgkND1GOiu5MM0E1rncYC6PLtF7JV"
+		storeKey   = "kudryavka"
+	)
+
+	received := map[string][]string{}
+	newPayloadHandler := func(name string) PayloadHandler {
+		return func(w http.ResponseWriter, r *http.Request, payload *Payload) {
+			received[name] = append(received[name], payload.UUID)
+			w.Write([]byte(name))
+		}
+	}
+
+	m := NewMux()
+	bot := m.Add(conf.NewAppConfig("cli_bot", "s"), conf.NewWebhookConfig(botToken, ""), newPayloadHandler("bot"))
+	store1 := m.Add(conf.NewAppConfig("cli_store1", "s"), conf.NewWebhookConfig(storeToken, storeKey), newPayloadHandler("store1"))
+	store2 := m.Add(conf.NewAppConfig("cli_store2", "s"), conf.NewWebhookConfig(storeToken, storeKey), newPayloadHandler("store2"))
+	assert.Equal(bot, m.Handler("cli_bot"))
+	assert.Equal(store2, m.AppTicketProvider("cli_store2"))
+	assert.Nil(m.Handler("cli_xxx"))
+	assert.Nil(m.AppTicketProvider("cli_xxx"))
+	assert.Panics(func() {
+		m.Add(conf.NewAppConfig("cli_bot", "s"), conf.NewWebhookConfig(botToken, ""), nil)
+	})
+
+	post := func(body []byte, header http.Header) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		r := httptest.NewRequest("POST", "/", bytes.NewReader(body))
+		for k, vs := range header {
+			r.Header[k] = vs
+		}
+		m.ServeHTTP(w, r)
+		return w
+	}
+	encrypt := func(body string) ([]byte, http.Header) {
+		encrypted, err := Encrypt(storeKey, []byte(body))
+		assert.NoError(err)
+		b, _ := json.Marshal(map[string]string{"encrypt": encrypted})
+		header := http.Header{}
+		header.Set(HeaderRequestTimestamp, "1")
+		header.Set(HeaderRequestNonce, "2")
+		header.Set(HeaderSignature, Signature("1", "2", storeKey, b))
+		return b, header
+	}
+
+	// 明文, 1.0 版本
+	{
+		w := post([]byte(fmt.Sprintf(`{
+			"uuid": "1", "token": "%s", "ts": "1", "type": "event_callback",
+			"event": {"type": "user_add", "app_id": "cli_bot", "open_id": "ou_1"}
+		}`, botToken)), nil)
+		assert.Equal(200, w.Code)
+		assert.Equal("bot", w.Body.String())
+	}
+
+	// 加密, 2.0 版本, 两个应用共用 token/秘钥, 按 app_id 分派
+	for i, appId := range []string{"cli_store1", "cli_store2"} {
+		body, header := encrypt(fmt.Sprintf(`{
+			"schema": "2.0",
+			"header": {"event_id": "e%d", "event_type": "app_ticket", "token": "%s", "app_id": "%s"},
+			"event": {"type": "app_ticket", "app_id": "%s", "app_ticket": "ticket_%d"}
+		}`, i, storeToken, appId, appId, i))
+		w := post(body, header)
+		assert.Equal(200, w.Code)
+	}
+	assert.Equal(map[string][]string{"bot": {"1"}, "store1": {"e0"}, "store2": {"e1"}}, received)
+	{
+		ticket, err := store1.FeishuAppTicket()
+		assert.NoError(err)
+		assert.Equal("ticket_0", ticket)
+		ticket, err = m.AppTicketProvider("cli_store2").FeishuAppTicket()
+		assert.NoError(err)
+		assert.Equal("ticket_1", ticket)
+	}
+
+	// url_verification 由第一个校验通过的应用处理
+	{
+		body, header := encrypt(fmt.Sprintf(`{"challenge": "abc", "token": "%s", "type": "url_verification"}`, storeToken))
+		w := post(body, header)
+		assert.Equal(200, w.Code)
+		assert.JSONEq(`{"challenge": "abc"}`, w.Body.String())
+	}
+
+	// 未知应用
+	{
+		w := post([]byte(fmt.Sprintf(`{
+			"uuid": "2", "token": "%s", "ts": "1", "type": "event_callback",
+			"event": {"type": "user_add", "app_id": "cli_xxx", "open_id": "ou_1"}
+		}`, botToken)), nil)
+		assert.Equal(StatusInvalidToken, w.Code)
+	}
+
+	// token 错误
+	{
+		resp, payload, h := m.Handle(nil, []byte(`{"token": "xxx", "type": "url_verification"}`))
+		assert.Equal(StatusInvalidToken, resp.StatusCode)
+		assert.Nil(payload)
+		assert.Nil(h)
+	}
+
+	// payload 错误
+	{
+		resp, _, _ := m.Handle(nil, []byte(`xxx`))
+		assert.Equal(StatusInvalidPayload, resp.StatusCode)
+	}
+
+	// 秘钥不同的应用: 使用错误秘钥解密的应用被跳过
+	{
+		m2 := NewMux()
+		m2.Add(conf.NewAppConfig("cli_a", "s"), conf.NewWebhookConfig(storeToken, "key_a"), newPayloadHandler("a"))
+		m2.Add(conf.NewAppConfig("cli_b", "s"), conf.NewWebhookConfig(storeToken, "key_b"), newPayloadHandler("b"))
+
+		for i := 0; i < 20; i++ {
+			plain := fmt.Sprintf(`{
+				"schema": "2.0",
+				"header": {"event_id": "b%d", "event_type": "user_add", "token": "%s", "app_id": "cli_b"},
+				"event": {"type": "user_add", "open_id": "ou_%d"}
+			}`, i, storeToken, i)
+			encrypted, err := Encrypt("key_b", []byte(plain))
+			assert.NoError(err)
+			b, _ := json.Marshal(map[string]string{"encrypt": encrypted})
+			resp, payload, h := m2.Handle(nil, b)
+			assert.Equal(200, resp.StatusCode)
+			assert.NotNil(payload)
+			assert.Equal(m2.Handler("cli_b"), h)
+		}
+
+		// 都不匹配
+		encrypted, err := Encrypt("key_c", []byte(`{"token": "x"}`))
+		assert.NoError(err)
+		b, _ := json.Marshal(map[string]string{"encrypt": encrypted})
+		resp, _, _ := m2.Handle(nil, b)
+		assert.Contains([]int{StatusDecryptError, StatusInvalidPayload}, resp.StatusCode)
+	}
+
+	// 仅有加密应用时解密失败
+	{
+		m2 := NewMux()
+		m2.Add(conf.NewAppConfig("cli_store1", "s"), conf.NewWebhookConfig(storeToken, storeKey), nil)
+		resp, _, _ := m2.Handle(nil, []byte(`{"encrypt": "xxx"}`))
+		assert.Equal(StatusDecryptError, resp.StatusCode)
+	}
+}
@@ -0,0 +1,76 @@
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+)
+
+// PayloadFunc 是与 net/http 无关的 payload 处理函数, 用于重放/长连接等场景
+type PayloadFunc func(ctx context.Context, payload *Payload) error
+
+// PayloadFunc 返回一个将 payload 交给该 Handler 的 PayloadHandler 处理的 PayloadFunc (使用模拟的 http 请求/响应)，
+// 请求 body 是解析 payload 时的原始明文; 响应非 2xx 视为错误
+func (h *Handler) PayloadFunc() PayloadFunc {
+	return func(ctx context.Context, payload *Payload) error {
+		body := payload.plainText
+		if body == nil {
+			// 不是解析得到的 payload
+			var err error
+			body, err = json.Marshal(payload)
+			if err != nil {
+				return err
+			}
+		}
+		return h.dispatch(ctx, nil, body, payload)
+	}
+}
+
+func (h *Handler) dispatch(ctx context.Context, header http.Header, body []byte, payload *Payload) error {
+	r, err := http.NewRequestWithContext(ctx, "POST", "/", bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	for k, vs := range header {
+		r.Header[k] = vs
+	}
+	w := &statusRecorder{header: http.Header{}}
+	h.handler(w, r, payload)
+	if code := w.statusCode(); code < 200 || code >= 300 {
+		return fmt.Errorf("PayloadHandler responded %d", code)
+	}
+	return nil
+}
+
+// statusRecorder 是只记录状态码的 http.ResponseWriter, 响应内容直接丢弃
+type statusRecorder struct {
+	header http.Header
+	code   int
+}
+
+func (w *statusRecorder) Header() http.Header {
+	return w.header
+}
+
+func (w *statusRecorder) Write(b []byte) (int, error) {
+	if w.code == 0 {
+		w.code = 200
+	}
+	return len(b), nil
+}
+
+func (w *statusRecorder) WriteHeader(code int) {
+	if w.code == 0 {
+		w.code = code
+	}
+}
+
+// statusCode 返回状态码, 没有写入任何内容时视为 200
+func (w *statusRecorder) statusCode() int {
+	if w.code == 0 {
+		return 200
+	}
+	return w.code
+}
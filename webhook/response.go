@@ -0,0 +1,54 @@
+package webhook
+
+import (
+	"encoding/json"
+	"net/http"
+)
+
+// Response 是处理 webhook 请求后应返回给飞书的响应
+type Response struct {
+	StatusCode  int
+	ContentType string
+	Body        []byte
+}
+
+func newOKResponse() *Response {
+	return &Response{
+		StatusCode:  200,
+		ContentType: "text/plain; charset=utf-8",
+		Body:        []byte("ok"),
+	}
+}
+
+func newErrorResponse(msg string, code int) *Response {
+	// 与 http.Error 保持一致
+	return &Response{
+		StatusCode:  code,
+		ContentType: "text/plain; charset=utf-8",
+		Body:        []byte(msg + "\n"),
+	}
+}
+
+func newJSONResponse(v interface{}) *Response {
+	body, err := json.Marshal(v)
+	if err != nil {
+		return newErrorResponse(err.Error(), 500)
+	}
+	return &Response{
+		StatusCode:  200,
+		ContentType: "application/json",
+		Body:        body,
+	}
+}
+
+// Respond 将响应写入 http.ResponseWriter
+func (resp *Response) Respond(w http.ResponseWriter) {
+	if resp.ContentType != "" {
+		w.Header().Set("Content-Type", resp.ContentType)
+	}
+	if resp.StatusCode != 200 {
+		w.Header().Set("X-Content-Type-Options", "nosniff")
+	}
+	w.WriteHeader(resp.StatusCode)
+	w.Write(resp.Body)
+}
@@ -1,11 +1,15 @@
 package webhook
 
 import (
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
 	"encoding/json"
 	"fmt"
 	"io/ioutil"
 	"net/http"
 	"sync/atomic"
+	"time"
 
 	"github.com/tidwall/gjson"
 
@@ -18,8 +22,15 @@ var (
 	_ conf.AppTicketProvider = (*Handler)(nil)
 )
 
-// Payload 是订阅事件的 payload
+// Payload 是订阅事件的 payload, 同时支持 1.0 和 2.0 版本，2.0 版本的 payload 在解析后会将
+// header 中的对应字段填到 Type/Token/Timestamp/UUID 中
 type Payload struct {
+	// Schema 是 payload 版本, 2.0 版本为 "2.0", 1.0 版本为空
+	Schema string `json:"schema"`
+
+	// Header 是 2.0 版本 payload 的头部, 1.0 版本为 nil
+	Header *PayloadHeader `json:"header"`
+
 	// Type 是类型: event_callback-事件推送，url_verification-url地址验证
 	Type string `json:"type"`
 
@@ -38,19 +49,53 @@ type Payload struct {
 	// RawEvent 是未解析的事件内容. type 为 event_callback 时有
 	RawEvent json.RawMessage `json:"event"`
 
-	event interface{}
+	event     interface{}
+	plainText []byte // 解析时的原始明文
+}
+
+// PayloadHeader 是 2.0 版本 payload 的头部
+type PayloadHeader struct {
+	EventId    string `json:"event_id"`
+	EventType  string `json:"event_type"`
+	CreateTime string `json:"create_time"`
+	Token      string `json:"token"`
+	AppId      string `json:"app_id"`
+	TenantKey  string `json:"tenant_key"`
+}
+
+// UndecodableEvent 在事件无法解析到注册的类型时 (例如飞书修改了字段类型) 作为事件返回,
+// 仅当使用了 HFallbackRawEvent 选项时出现
+type UndecodableEvent struct {
+	// Type 是事件类型
+	Type string
+
+	// Raw 是未解析的事件内容
+	Raw json.RawMessage
+
+	// Err 是解析时的错误
+	Err error
 }
 
 // PayloadHandler 用于处理 webhook payload
 type PayloadHandler func(w http.ResponseWriter, r *http.Request, payload *Payload)
 
 // Handler 是一个 http.Handler，用于处理订阅事件回调/自动处理 url_verification，
+// 不使用 net/http 时也可以直接调用 Handle;
 // 同时它也满足 AppTicketProvider, 可为应用商店应用提供 app ticket
 type Handler struct {
 	verifToken string
+	encryptKey string
 	decrypter  *decrypter
 	handler    PayloadHandler
 
+	registry      *Registry
+	ownedRegistry bool // registry 是否为该 Handler 独有
+
+	onDecodeError    func(*Payload, error)
+	fallbackRawEvent bool
+	metricsHook      func(string, error)
+	journal          Journal
+
 	appTicket atomic.Value // string
 }
 
@@ -62,18 +107,36 @@ func (payload *Payload) GetEvent() interface{} {
 	return payload.event
 }
 
+// EventType 返回事件类型, 1.0 版本是 event.type 字段，2.0 版本是 header.event_type 字段
+func (payload *Payload) EventType() string {
+	if payload.Header != nil {
+		return payload.Header.EventType
+	}
+	return gjson.GetBytes(payload.RawEvent, "type").Str
+}
+
+// AppId 返回事件所属应用的 app_id, 1.0 版本是 event.app_id 字段，2.0 版本是 header.app_id 字段;
+// url_verification 没有 app_id
+func (payload *Payload) AppId() string {
+	if payload.Header != nil {
+		return payload.Header.AppId
+	}
+	return gjson.GetBytes(payload.RawEvent, "app_id").Str
+}
+
 // New 创建一个 Handler，cnf 必须提供，handler 可用于处理感兴趣的事件，
-// 也可以传入 nil
-func New(cnf conf.WebhookConfig, handler PayloadHandler) *Handler {
+// 也可以传入 nil; 选项错误时会 panic
+func New(cnf conf.WebhookConfig, handler PayloadHandler, opts ...HandlerOption) *Handler {
 
 	verifToken := cnf.FeishuWebhookVerifToken()
 	if verifToken == "" {
 		panic(fmt.Errorf("Empty feishu verify token"))
 	}
 
+	encryptKey := cnf.FeishuWebhookEncryptKey()
 	var decrypter *decrypter
-	if key := cnf.FeishuWebhookEncryptKey(); key != "" {
-		decrypter = newDecrypter(key)
+	if encryptKey != "" {
+		decrypter = newDecrypter(encryptKey)
 	}
 
 	if handler == nil {
@@ -82,15 +145,25 @@ func New(cnf conf.WebhookConfig, handler PayloadHandler) *Handler {
 		}
 	}
 
-	return &Handler{
-		verifToken: verifToken,
-		decrypter:  decrypter,
-		handler:    handler,
+	h := &Handler{
+		verifToken:    verifToken,
+		encryptKey:    encryptKey,
+		decrypter:     decrypter,
+		handler:       handler,
+		registry:      DefaultRegistry,
+		onDecodeError: func(*Payload, error) {},
+		metricsHook:   func(string, error) {},
+	}
+	for _, opt := range opts {
+		if err := opt(h); err != nil {
+			panic(err)
+		}
 	}
+	return h
 
 }
 
-// ServeHTTP 满足 http.Handler 接口
+// ServeHTTP 满足 http.Handler 接口, 基于 Handle 实现
 func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 
 	body, err := ioutil.ReadAll(r.Body)
@@ -99,74 +172,161 @@ func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	invalidPayload := func(code int) {
-		http.Error(w, "Invalid payload", code)
+	resp, payload := h.Handle(r.Header, body)
+	if payload == nil {
+		resp.Respond(w)
+		return
+	}
+	h.handler(w, r, payload)
+
+}
+
+// Handle 是与 net/http 无关的处理入口，可用于 serverless 函数/消息队列/其它 web 框架等场景:
+// 它对请求 body 进行签名校验/解密/解析/token 校验，自动处理 url_verification，并将事件解析为注册的类型.
+//
+// 返回的 payload 非 nil 时表示需要由业务处理 (此时 resp 为默认的成功响应，调用者可自行替换)，
+// 否则直接将 resp 返回给飞书即可
+func (h *Handler) Handle(header http.Header, body []byte) (resp *Response, payload *Payload) {
+	payload, body, resp = h.verify(header, body)
+	if resp != nil {
+		return resp, nil
+	}
+	return h.process(header, body, payload)
+}
+
+// verify 对请求 body 进行签名校验/解密/解析/token 校验，返回 payload 及明文 body; 失败时返回非 nil 的 resp
+func (h *Handler) verify(header http.Header, body []byte) (payload *Payload, plainText []byte, resp *Response) {
+
+	invalidPayload := func(code int) (*Payload, []byte, *Response) {
+		return nil, nil, newErrorResponse("Invalid payload", code)
 	}
 
 	// 加密模式
 	if h.decrypter != nil {
-		encryptPayload := &struct {
-			Encrypt string `json:"encrypt"`
-		}{}
-		err := json.Unmarshal(body, encryptPayload)
-		if err != nil {
-			invalidPayload(461)
-			return
+		// 带签名时校验签名
+		if sig := header.Get(HeaderSignature); sig != "" {
+			expected := Signature(
+				header.Get(HeaderRequestTimestamp),
+				header.Get(HeaderRequestNonce),
+				h.encryptKey,
+				body,
+			)
+			if subtle.ConstantTimeCompare([]byte(expected), []byte(sig)) != 1 {
+				return invalidPayload(StatusInvalidToken)
+			}
 		}
 
-		body, err = h.decrypter.Decrypt(encryptPayload.Encrypt)
+		var err error
+		body, err = h.decrypter.DecryptBody(body)
 		if err != nil {
-			invalidPayload(461)
-			return
+			return invalidPayload(StatusDecryptError)
 		}
 	}
 
-	payload := new(Payload)
-	err = json.Unmarshal(body, payload)
+	payload, err := parsePayload(body)
 	if err != nil {
-		invalidPayload(462)
-		return
+		return invalidPayload(StatusInvalidPayload)
 	}
 
 	// 验证 token
 	if payload.Token != h.verifToken {
-		invalidPayload(463)
-		return
+		return invalidPayload(StatusInvalidToken)
 	}
 
-	switch payload.Type {
-	default:
-		h.handler(w, r, payload)
-		return
+	return payload, body, nil
 
+}
+
+// process 处理校验后的 payload: 记录 journal，自动处理 url_verification，将事件解析为注册的类型
+func (h *Handler) process(header http.Header, body []byte, payload *Payload) (*Response, *Payload) {
+
+	// 记录校验及解密后的 payload
+	if h.journal != nil {
+		err := h.journal.Append(&JournalEntry{
+			ReceivedAt: time.Now(),
+			Header:     header,
+			Payload:    json.RawMessage(body),
+		})
+		if err != nil {
+			return newErrorResponse("Journal error", 500), nil
+		}
+	}
+
+	switch payload.Type {
 	case PayloadTypeURLVerification:
-		// NOTE: 这里忽略错误
-		json.NewEncoder(w).Encode(map[string]interface{}{
+		return newJSONResponse(map[string]interface{}{
 			"challenge": payload.Challenge,
-		})
-		return
+		}), nil
 
 	case PayloadTypeEventCallback:
-		newEv := newEvMaps[gjson.GetBytes(payload.RawEvent, "type").Str]
-		if newEv == nil {
-			newEv = newEvMaps[""]
+		if err := h.decodeEvent(payload); err != nil {
+			return newErrorResponse("Decode event error", 500), nil
+		}
+		if e, ok := payload.event.(*events.AppTicket); ok {
+			h.appTicket.Store(e.AppTicket)
 		}
-		ev := newEv()
+	}
 
-		if err := json.Unmarshal(payload.RawEvent, ev); err != nil {
-			panic(err)
+	return newOKResponse(), payload
+
+}
+
+// decodeEvent 将 payload.RawEvent 解析为注册的事件类型，解析失败且没有开启 HFallbackRawEvent 时返回错误
+func (h *Handler) decodeEvent(payload *Payload) error {
+	typ := payload.EventType()
+	ev := h.registry.NewEvent(typ)
+
+	err := json.Unmarshal(payload.RawEvent, ev)
+	h.metricsHook(typ, err)
+	if err != nil {
+		h.onDecodeError(payload, err)
+		if !h.fallbackRawEvent {
+			return err
 		}
-		payload.event = ev
+		ev = &UndecodableEvent{
+			Type: typ,
+			Raw:  payload.RawEvent,
+			Err:  err,
+		}
+	}
+	payload.event = ev
+	return nil
+}
 
-		switch e := ev.(type) {
-		case *events.AppTicket:
-			h.appTicket.Store(e.AppTicket)
+// ParsePayload 跳过签名/token 校验，解析明文 payload, 若是 event_callback 则将事件解析为注册的类型;
+// 用于已经可信的来源，如长连接/消息队列等
+func (h *Handler) ParsePayload(plainText []byte) (*Payload, error) {
+	payload, err := parsePayload(plainText)
+	if err != nil {
+		return nil, err
+	}
+	if payload.Type == PayloadTypeEventCallback {
+		if err := h.decodeEvent(payload); err != nil {
+			return nil, err
 		}
+	}
+	return payload, nil
+}
 
-		h.handler(w, r, payload)
-		return
+// parsePayload 解析明文 payload
+func parsePayload(plainText []byte) (*Payload, error) {
+	payload := new(Payload)
+	if err := json.Unmarshal(plainText, payload); err != nil {
+		return nil, err
 	}
+	payload.plainText = plainText
 
+	// 2.0 版本
+	if payload.Schema == "2.0" {
+		if payload.Header == nil {
+			return nil, fmt.Errorf("Missing header in schema 2.0 payload")
+		}
+		payload.Type = PayloadTypeEventCallback
+		payload.Token = payload.Header.Token
+		payload.Timestamp = payload.Header.CreateTime
+		payload.UUID = payload.Header.EventId
+	}
+	return payload, nil
 }
 
 // FeishuAppTicket 满足 AppTicketProvider 接口
@@ -177,3 +337,14 @@ func (h *Handler) FeishuAppTicket() (string, error) {
 	}
 	return v.(string), nil
 }
+
+// Signature 计算事件回调的签名: sha256(timestamp + nonce + encryptKey + body)，
+// 详见 https://open.feishu.cn/document/ukTMukTMukTM/uYDNxYjL2QTM24iN0EjN/event-subscription-configure-/encrypt-key-encryption-configuration-case
+func Signature(timestamp, nonce, encryptKey string, body []byte) string {
+	hash := sha256.New()
+	hash.Write([]byte(timestamp))
+	hash.Write([]byte(nonce))
+	hash.Write([]byte(encryptKey))
+	hash.Write(body)
+	return hex.EncodeToString(hash.Sum(nil))
+}
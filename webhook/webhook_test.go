@@ -0,0 +1,226 @@
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/huangjunwen/feishu-driver/conf"
+	"github.com/huangjunwen/feishu-driver/webhook/events"
+)
+
+func TestHandlerDecodeError(t *testing.T) {
+	assert := assert.New(t)
+
+	verifToken := "GzhQEyfUcx7eEungQFWtXgCbxSpUOJIb"
+	cnf := conf.NewWebhookConfig(verifToken, "")
+
+	post := func(h *Handler, event string) *httptest.ResponseRecorder {
+		body := fmt.Sprintf(`{
+			"uuid": "5226cd85b4d843dccee2e279d93f3ed3",
+			"token": "%s",
+			"ts": "1589970805.376395",
+			"type": "event_callback",
+			"event": %s
+		}`, verifToken, event)
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, httptest.NewRequest("POST", "/", bytes.NewBufferString(body)))
+		return w
+	}
+
+	// 字段类型错误: seats 应该是数字
+	badEvent := `{"type": "order_paid", "app_id": "cli_9e28cb7ba56a100e", "seats": "10"}`
+	goodEvent := `{
+		"type": "message",
+		"app_id": "cli_9e28cb7ba56a100e",
+		"root_id": "om_root",
+		"image_height": "300",
+		"msg_type": "image"
+	}`
+
+	// 默认: 返回 500，不交给 handler
+	{
+		var (
+			handled    bool
+			decodeErr  error
+			metricsErr error
+			metricsTyp string
+		)
+		h := New(cnf, func(w http.ResponseWriter, r *http.Request, payload *Payload) {
+			handled = true
+		}, HOnDecodeError(func(payload *Payload, err error) {
+			decodeErr = err
+		}), HMetricsHook(func(typ string, err error) {
+			metricsTyp = typ
+			metricsErr = err
+		}))
+
+		w := post(h, badEvent)
+		assert.Equal(500, w.Code)
+		assert.False(handled)
+		assert.Error(decodeErr)
+		assert.Error(metricsErr)
+		assert.Equal("order_paid", metricsTyp)
+	}
+
+	// 开启 fallback: 交给 handler, 事件为 *UndecodableEvent
+	{
+		var ev interface{}
+		h := New(cnf, func(w http.ResponseWriter, r *http.Request, payload *Payload) {
+			ev = payload.GetEvent()
+			w.Write([]byte("ok"))
+		}, HFallbackRawEvent(true))
+
+		w := post(h, badEvent)
+		assert.Equal(200, w.Code)
+		if assert.IsType(&UndecodableEvent{}, ev) {
+			undecodable := ev.(*UndecodableEvent)
+			assert.Equal("order_paid", undecodable.Type)
+			assert.Error(undecodable.Err)
+		}
+	}
+
+	// 正常解析，包括之前 tag 写错的字段
+	{
+		var ev interface{}
+		h := New(cnf, func(w http.ResponseWriter, r *http.Request, payload *Payload) {
+			ev = payload.GetEvent()
+			w.Write([]byte("ok"))
+		})
+
+		w := post(h, goodEvent)
+		assert.Equal(200, w.Code)
+		if assert.IsType(&events.Message{}, ev) {
+			msg := ev.(*events.Message)
+			assert.Equal("om_root", msg.RootId)
+			assert.Equal("300", msg.ImageHeight)
+		}
+	}
+
+}
+
+func TestHandle(t *testing.T) {
+	assert := assert.New(t)
+
+	verifToken := "GzhQEyfUcx7eEungQFWtXgCbxSpUOJIb"
+	h := New(conf.NewWebhookConfig(verifToken, ""), nil)
+
+	// url_verification: 直接返回响应
+	{
+		resp, payload := h.Handle(nil, []byte(`{"challenge": "ajls384kdjx98XX", "token": "`+verifToken+`", "type": "url_verification"}`))
+		assert.Nil(payload)
+		assert.Equal(200, resp.StatusCode)
+		assertJSONEqual(assert, []byte(`{"challenge": "ajls384kdjx98XX"}`), resp.Body)
+	}
+
+	// 无效 payload
+	{
+		resp, payload := h.Handle(nil, []byte(`xxx`))
+		assert.Nil(payload)
+		assert.Equal(StatusInvalidPayload, resp.StatusCode)
+	}
+
+	// token 错误
+	{
+		resp, payload := h.Handle(nil, []byte(`{"schema": "2.0", "header": {"event_type": "app_ticket", "token": "xxx"}, "event": {}}`))
+		assert.Nil(payload)
+		assert.Equal(StatusInvalidToken, resp.StatusCode)
+	}
+
+	// 事件: 返回 payload 交由调用者处理
+	{
+		resp, payload := h.Handle(http.Header{}, []byte(`{
+			"schema": "2.0",
+			"header": {
+				"event_id": "f7984f25108f8137722bb63cee927e66",
+				"event_type": "app_ticket",
+				"create_time": "1603977298000",
+				"token": "`+verifToken+`",
+				"app_id": "cli_9e28cb7ba56a100e"
+			},
+			"event": {"app_id": "cli_9e28cb7ba56a100e", "app_ticket": "ticket"}
+		}`))
+		assert.Equal(200, resp.StatusCode)
+		if assert.NotNil(payload) {
+			assert.Equal("f7984f25108f8137722bb63cee927e66", payload.UUID)
+			assert.IsType(&events.AppTicket{}, payload.GetEvent())
+		}
+	}
+
+}
+
+func TestHandlerRegistry(t *testing.T) {
+	assert := assert.New(t)
+
+	verifToken := "GzhQEyfUcx7eEungQFWtXgCbxSpUOJIb"
+	cnf := conf.NewWebhookConfig(verifToken, "")
+
+	type MyAppTicket struct {
+		AppTicket string `json:"app_ticket"`
+	}
+	type ChatCreated struct {
+		ChatId string `json:"chat_id"`
+	}
+
+	handle := func(h *Handler, body string) interface{} {
+		_, payload := h.Handle(nil, []byte(body))
+		if payload == nil {
+			return nil
+		}
+		return payload.GetEvent()
+	}
+	v1Body := `{"uuid": "1", "token": "` + verifToken + `", "type": "event_callback", "event": {"type": "app_ticket", "app_ticket": "ticket"}}`
+	v2Body := `{"schema": "2.0", "header": {"event_id": "2", "event_type": "im.chat.created_v1", "token": "` + verifToken + `"}, "event": {"chat_id": "oc_1"}}`
+
+	h1 := New(cnf, nil)
+	h2 := New(cnf, nil,
+		HRegistEv("app_ticket", func() interface{} { return new(MyAppTicket) }),
+		HRegistEv("im.chat.created_v1", func() interface{} { return new(ChatCreated) }),
+	)
+	h3 := New(cnf, nil, HRegistry(NewRegistry()))
+
+	// h1 使用默认注册表
+	assert.IsType(&events.AppTicket{}, handle(h1, v1Body))
+	assert.IsType(&events.Unsupported{}, handle(h1, v2Body))
+
+	// h2 覆盖了内置类型并注册了 2.0 类型
+	assert.Equal(&MyAppTicket{AppTicket: "ticket"}, handle(h2, v1Body))
+	assert.Equal(&ChatCreated{ChatId: "oc_1"}, handle(h2, v2Body))
+
+	// h3 使用空注册表
+	assert.IsType(&events.Unsupported{}, handle(h3, v1Body))
+
+	// 默认注册表不受影响
+	assert.IsType(&events.AppTicket{}, DefaultRegistry.NewEvent("app_ticket"))
+	assert.NotContains(DefaultRegistry.Types(), "im.chat.created_v1")
+}
+
+func TestPayloadFunc(t *testing.T) {
+	assert := assert.New(t)
+
+	verifToken := "GzhQEyfUcx7eEungQFWtXgCbxSpUOJIb"
+	var body []byte
+	h := New(conf.NewWebhookConfig(verifToken, ""), func(w http.ResponseWriter, r *http.Request, payload *Payload) {
+		body, _ = ioutil.ReadAll(r.Body)
+		if payload.UUID == "bad" {
+			w.WriteHeader(500)
+		}
+	})
+
+	// 原始明文原样交给 PayloadHandler, 包括 Payload 中没有的字段
+	plainText := `{"uuid": "1", "token": "` + verifToken + `", "ts": "1", "type": "event_callback", "extra": "x", "event": {"type": "user_add", "open_id": "ou_1"}}`
+	payload, err := h.ParsePayload([]byte(plainText))
+	assert.NoError(err)
+	assert.NoError(h.PayloadFunc()(context.Background(), payload))
+	assert.Equal(plainText, string(body))
+
+	payload, err = h.ParsePayload([]byte(`{"uuid": "bad", "type": "event_callback", "event": {"type": "user_add"}}`))
+	assert.NoError(err)
+	assert.Error(h.PayloadFunc()(context.Background(), payload))
+}
@@ -0,0 +1,3 @@
+// Package webhooktest 包含用于测试 webhook 处理逻辑的工具: 构造 1.0/2.0 版本的 payload,
+// 加密/签名，并推送到运行 webhook.Handler 的测试服务器
+package webhooktest
@@ -0,0 +1,147 @@
+package webhooktest
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/huangjunwen/feishu-driver/webhook"
+	"github.com/huangjunwen/feishu-driver/webhook/events"
+)
+
+// EventTypes 返回 events.Regist 中注册的所有事件类型 (不包括未支持类型 "")
+func EventTypes() []string {
+	typs := []string{}
+	events.Regist(func(typ string, _ func() interface{}) {
+		if typ != "" {
+			typs = append(typs, typ)
+		}
+	})
+	sort.Strings(typs)
+	return typs
+}
+
+// NewEvent 使用 events.Regist 中注册的工厂函数创建一个 typ 类型的空事件，未注册时返回 nil
+func NewEvent(typ string) interface{} {
+	var ev interface{}
+	events.Regist(func(t string, newEv func() interface{}) {
+		if t == typ && typ != "" {
+			ev = newEv()
+		}
+	})
+	return ev
+}
+
+// IsSchema2 判断事件类型是否是 2.0 版本的事件类型 (形如 im.message.receive_v1)
+func IsSchema2(typ string) bool {
+	return strings.Contains(typ, ".")
+}
+
+// NewPayload 根据事件类型构造 1.0 或 2.0 版本的 event_callback payload, ev 为 nil 时使用 NewEvent(typ)
+func NewPayload(verifToken, typ string, ev interface{}) ([]byte, error) {
+	if IsSchema2(typ) {
+		return NewPayloadV2(verifToken, typ, ev)
+	}
+	return NewPayloadV1(verifToken, typ, ev)
+}
+
+// NewPayloadV1 构造 1.0 版本的 event_callback payload, ev 为 nil 时使用 NewEvent(typ);
+// 事件内容中的 type 字段会被设置为 typ
+func NewPayloadV1(verifToken, typ string, ev interface{}) ([]byte, error) {
+	rawEvent, err := marshalEvent(typ, ev)
+	if err != nil {
+		return nil, err
+	}
+
+	fields := map[string]interface{}{}
+	if err := json.Unmarshal(rawEvent, &fields); err != nil {
+		return nil, err
+	}
+	fields["type"] = typ
+
+	return json.Marshal(map[string]interface{}{
+		"uuid":  newUUID(),
+		"token": verifToken,
+		"ts":    strconv.FormatFloat(float64(time.Now().UnixNano())/1e9, 'f', 6, 64),
+		"type":  webhook.PayloadTypeEventCallback,
+		"event": fields,
+	})
+}
+
+// NewPayloadV2 构造 2.0 版本的 payload, ev 为 nil 时使用 NewEvent(typ)
+func NewPayloadV2(verifToken, typ string, ev interface{}) ([]byte, error) {
+	rawEvent, err := marshalEvent(typ, ev)
+	if err != nil {
+		return nil, err
+	}
+
+	return json.Marshal(map[string]interface{}{
+		"schema": "2.0",
+		"header": &webhook.PayloadHeader{
+			EventId:    newUUID(),
+			EventType:  typ,
+			CreateTime: strconv.FormatInt(time.Now().UnixNano()/int64(time.Millisecond), 10),
+			Token:      verifToken,
+		},
+		"event": json.RawMessage(rawEvent),
+	})
+}
+
+// NewURLVerificationPayload 构造 url_verification payload
+func NewURLVerificationPayload(verifToken, challenge string) []byte {
+	b, err := json.Marshal(map[string]interface{}{
+		"challenge": challenge,
+		"token":     verifToken,
+		"type":      webhook.PayloadTypeURLVerification,
+	})
+	if err != nil {
+		panic(err)
+	}
+	return b
+}
+
+// EncryptBody 使用 encryptKey 加密 payload, 返回形如 {"encrypt": "..."} 的 body
+func EncryptBody(encryptKey string, payload []byte) ([]byte, error) {
+	cipherText, err := webhook.Encrypt(encryptKey, payload)
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(map[string]interface{}{
+		"encrypt": cipherText,
+	})
+}
+
+// SignHeader 返回带有签名的请求头部
+func SignHeader(encryptKey string, body []byte) http.Header {
+	timestamp := strconv.FormatInt(time.Now().Unix(), 10)
+	nonce := newUUID()
+	header := http.Header{}
+	header.Set(webhook.HeaderRequestTimestamp, timestamp)
+	header.Set(webhook.HeaderRequestNonce, nonce)
+	header.Set(webhook.HeaderSignature, webhook.Signature(timestamp, nonce, encryptKey, body))
+	return header
+}
+
+func marshalEvent(typ string, ev interface{}) ([]byte, error) {
+	if ev == nil {
+		ev = NewEvent(typ)
+		if ev == nil {
+			return nil, fmt.Errorf("Event type %q not registered", typ)
+		}
+	}
+	return json.Marshal(ev)
+}
+
+func newUUID() string {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		panic(err)
+	}
+	return hex.EncodeToString(b)
+}
@@ -0,0 +1,148 @@
+package webhooktest
+
+import (
+	"bytes"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+
+	"github.com/huangjunwen/feishu-driver/conf"
+)
+
+// Simulator 启动一个运行 handler (一般是 *webhook.Handler) 的 httptest.Server,
+// 并模拟飞书向其推送事件
+type Simulator struct {
+	// Server 是运行 handler 的测试服务器
+	Server *httptest.Server
+
+	// Encrypt 为 true 时使用配置中的 Encrypt Key 加密 payload
+	Encrypt bool
+
+	// Sign 为 true 时在请求头部添加签名 (需要配置了 Encrypt Key)
+	Sign bool
+
+	cnf conf.WebhookConfig
+}
+
+// Response 是模拟推送得到的响应
+type Response struct {
+	StatusCode int
+	Body       []byte
+}
+
+// NewSimulator 创建一个 Simulator, 若配置了 Encrypt Key 则默认加密并签名, 用完后需要 Close
+func NewSimulator(cnf conf.WebhookConfig, handler http.Handler) *Simulator {
+	encrypt := cnf.FeishuWebhookEncryptKey() != ""
+	return &Simulator{
+		Server:  httptest.NewServer(handler),
+		Encrypt: encrypt,
+		Sign:    encrypt,
+		cnf:     cnf,
+	}
+}
+
+// Close 关闭测试服务器
+func (s *Simulator) Close() {
+	s.Server.Close()
+}
+
+// PostEvent 构造 typ 类型的事件 payload (见 NewPayload) 并推送，ev 为 nil 时使用 NewEvent(typ)
+func (s *Simulator) PostEvent(typ string, ev interface{}) (*Response, error) {
+	payload, err := NewPayload(s.cnf.FeishuWebhookVerifToken(), typ, ev)
+	if err != nil {
+		return nil, err
+	}
+	return s.PostPayload(payload)
+}
+
+// PostURLVerification 推送 url_verification
+func (s *Simulator) PostURLVerification(challenge string) (*Response, error) {
+	return s.PostPayload(NewURLVerificationPayload(s.cnf.FeishuWebhookVerifToken(), challenge))
+}
+
+// PostPayload 推送明文 payload, 根据设置加密/签名
+func (s *Simulator) PostPayload(payload []byte) (*Response, error) {
+	body := payload
+	if s.Encrypt {
+		var err error
+		body, err = EncryptBody(s.cnf.FeishuWebhookEncryptKey(), payload)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	header := http.Header{}
+	if s.Sign {
+		header = SignHeader(s.cnf.FeishuWebhookEncryptKey(), body)
+	}
+	return s.Post(body, header)
+}
+
+// Post 原样推送 body
+func (s *Simulator) Post(body []byte, header http.Header) (*Response, error) {
+	req, err := http.NewRequest("POST", s.Server.URL, bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	for k, vs := range header {
+		req.Header[k] = vs
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := s.Server.Client().Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	respBody, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	return &Response{
+		StatusCode: resp.StatusCode,
+		Body:       respBody,
+	}, nil
+}
+
+// AssertStatus 断言响应状态码
+func (resp *Response) AssertStatus(t testing.TB, statusCode int) bool {
+	t.Helper()
+	if resp.StatusCode != statusCode {
+		t.Errorf("Expect status code %d, but got %d: %q", statusCode, resp.StatusCode, resp.Body)
+		return false
+	}
+	return true
+}
+
+// AssertBody 断言响应 body
+func (resp *Response) AssertBody(t testing.TB, body string) bool {
+	t.Helper()
+	if string(resp.Body) != body {
+		t.Errorf("Expect body %q, but got %q", body, resp.Body)
+		return false
+	}
+	return true
+}
+
+// AssertJSON 断言响应 body 与 expected 是等价的 json
+func (resp *Response) AssertJSON(t testing.TB, expected string) bool {
+	t.Helper()
+	var expectedVal, actualVal interface{}
+	if err := json.Unmarshal([]byte(expected), &expectedVal); err != nil {
+		t.Errorf("Invalid expected json: %s", err)
+		return false
+	}
+	if err := json.Unmarshal(resp.Body, &actualVal); err != nil {
+		t.Errorf("Invalid response json %q: %s", resp.Body, err)
+		return false
+	}
+	if !reflect.DeepEqual(expectedVal, actualVal) {
+		t.Errorf("Expect json %s, but got %s", expected, resp.Body)
+		return false
+	}
+	return true
+}
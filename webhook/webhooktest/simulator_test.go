@@ -0,0 +1,90 @@
+package webhooktest
+
+import (
+	"net/http"
+	"reflect"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/huangjunwen/feishu-driver/conf"
+	"github.com/huangjunwen/feishu-driver/webhook"
+	"github.com/huangjunwen/feishu-driver/webhook/events"
+)
+
+func TestSimulator(t *testing.T) {
+	assert := assert.New(t)
+
+	for _, cnf := range []conf.WebhookConfig{
+		conf.NewWebhookConfig("GzhQEyfUcx7eEungQFWtXgCbxSpUOJIb", ""),
+		conf.NewWebhookConfig("GzhQEyfUcx7eEungQFWtXgCbxSpUOJIb", "kudryavka"),
+	} {
+		var lastPayload *webhook.Payload
+		h := webhook.New(cnf, func(w http.ResponseWriter, r *http.Request, payload *webhook.Payload) {
+			lastPayload = payload
+			w.Write([]byte("ok"))
+		})
+		s := NewSimulator(cnf, h)
+
+		// url_verification
+		resp, err := s.PostURLVerification("ajls384kdjx98XX")
+		assert.NoError(err)
+		resp.AssertStatus(t, 200)
+		resp.AssertJSON(t, `{"challenge": "ajls384kdjx98XX"}`)
+
+		// 所有注册的事件类型
+		for _, typ := range EventTypes() {
+			lastPayload = nil
+			resp, err := s.PostEvent(typ, nil)
+			assert.NoError(err)
+			resp.AssertStatus(t, 200)
+			resp.AssertBody(t, "ok")
+
+			if assert.NotNil(lastPayload, typ) {
+				assert.Equal(typ, lastPayload.EventType())
+				assert.Equal(reflect.TypeOf(NewEvent(typ)), reflect.TypeOf(lastPayload.GetEvent()), typ)
+			}
+		}
+
+		// 2.0 版本
+		{
+			lastPayload = nil
+			payload, err := NewPayloadV2(cnf.FeishuWebhookVerifToken(), "app_ticket", &events.AppTicket{
+				AppId:     "cli_9e28cb7ba56a100e",
+				AppTicket: "ticket",
+			})
+			assert.NoError(err)
+			resp, err := s.PostPayload(payload)
+			assert.NoError(err)
+			resp.AssertStatus(t, 200)
+			if assert.NotNil(lastPayload) {
+				assert.Equal("2.0", lastPayload.Schema)
+				assert.Equal(webhook.PayloadTypeEventCallback, lastPayload.Type)
+			}
+			ticket, err := h.FeishuAppTicket()
+			assert.NoError(err)
+			assert.Equal("ticket", ticket)
+		}
+
+		// token 错误
+		{
+			payload, err := NewPayloadV1("xxx", "app_ticket", nil)
+			assert.NoError(err)
+			resp, err := s.PostPayload(payload)
+			assert.NoError(err)
+			resp.AssertStatus(t, 463)
+		}
+
+		// 签名错误
+		if s.Sign {
+			body, err := EncryptBody(cnf.FeishuWebhookEncryptKey(), NewURLVerificationPayload(cnf.FeishuWebhookVerifToken(), "x"))
+			assert.NoError(err)
+			header := SignHeader("wrong key", body)
+			resp, err := s.Post(body, header)
+			assert.NoError(err)
+			resp.AssertStatus(t, 463)
+		}
+
+		s.Close()
+	}
+}